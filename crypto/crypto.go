@@ -1,10 +1,10 @@
 package crypto
 
 import (
-	"Hash/adler32"
 	"bytes"
 	"crypto/dsa"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/hmac"
 	"crypto/rand"
@@ -20,6 +20,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"hash/adler32"
 	"math/big"
 	"net"
 	"time"
@@ -33,6 +34,18 @@ func Sha256sum(input string) string {
 	return hex.EncodeToString(Hash[:])
 }
 
+// HmacSha256 returns the HMAC-SHA256 of message keyed with key.
+func HmacSha256(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// HmacSha256sum returns the hex-encoded HMAC-SHA256 of input keyed with key.
+func HmacSha256sum(key, input string) string {
+	return hex.EncodeToString(HmacSha256([]byte(key), []byte(input)))
+}
+
 func Sha1sum(input string) string {
 	Hash := sha1.Sum([]byte(input))
 	return hex.EncodeToString(Hash[:])
@@ -130,6 +143,8 @@ func GeneratePrivateKey(typ string) string {
 	case "ecdsa":
 		// again, good enough for government work
 		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ed25519":
+		_, priv, err = ed25519.GenerateKey(rand.Reader)
 	default:
 		return "Unknown type " + typ
 	}
@@ -159,6 +174,9 @@ func PemBlockForKey(priv interface{}) *pem.Block {
 	case *ecdsa.PrivateKey:
 		b, _ := x509.MarshalECPrivateKey(k)
 		return &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}
+	case ed25519.PrivateKey:
+		b, _ := x509.MarshalPKCS8PrivateKey(k)
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: b}
 	default:
 		return nil
 	}
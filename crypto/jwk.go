@@ -0,0 +1,328 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a JSON Web Key (RFC 7517) holding an RSA, elliptic curve, Ed25519 or symmetric key.
+// Private members are empty for public keys.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+
+	// RSA
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+
+	// EC and OKP
+	X string `json:"x,omitempty"`
+	Y string `json:"y,omitempty"`
+
+	// Private exponent or key of RSA, EC and OKP keys.
+	D string `json:"d,omitempty"`
+
+	// Symmetric
+	K string `json:"k,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set, as served by the jwks_uri of an OpenID provider.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+var b64 = base64.RawURLEncoding
+
+// ParsePrivateKey parses a PEM-encoded private key as returned by GeneratePrivateKey.
+// DSA keys are not supported.
+func ParsePrivateKey(data string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("unable to decode key")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", block.Type)
+	}
+}
+
+// NewJWK returns the JWK of key, which may be an RSA, ECDSA or Ed25519 private or public key,
+// or a []byte secret. If kid is empty, the key ID is set to the thumbprint of the key.
+func NewJWK(key interface{}, kid string) (*JWK, error) {
+	var jwk *JWK
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		jwk = rsaJWK(&k.PublicKey)
+		k.Precompute()
+		jwk.D = b64.EncodeToString(k.D.Bytes())
+		if len(k.Primes) == 2 {
+			jwk.P = b64.EncodeToString(k.Primes[0].Bytes())
+			jwk.Q = b64.EncodeToString(k.Primes[1].Bytes())
+			jwk.DP = b64.EncodeToString(k.Precomputed.Dp.Bytes())
+			jwk.DQ = b64.EncodeToString(k.Precomputed.Dq.Bytes())
+			jwk.QI = b64.EncodeToString(k.Precomputed.Qinv.Bytes())
+		}
+	case *rsa.PublicKey:
+		jwk = rsaJWK(k)
+	case *ecdsa.PrivateKey:
+		var err error
+		if jwk, err = ecJWK(&k.PublicKey); err != nil {
+			return nil, err
+		}
+		jwk.D = b64.EncodeToString(k.D.FillBytes(make([]byte, curveSize(k.Curve))))
+	case *ecdsa.PublicKey:
+		var err error
+		if jwk, err = ecJWK(k); err != nil {
+			return nil, err
+		}
+	case ed25519.PrivateKey:
+		jwk = &JWK{Kty: "OKP", Crv: "Ed25519", X: b64.EncodeToString(k.Public().(ed25519.PublicKey)), D: b64.EncodeToString(k.Seed())}
+	case ed25519.PublicKey:
+		jwk = &JWK{Kty: "OKP", Crv: "Ed25519", X: b64.EncodeToString(k)}
+	case []byte:
+		jwk = &JWK{Kty: "oct", K: b64.EncodeToString(k)}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	jwk.Kid = kid
+	if jwk.Kid == "" {
+		jwk.Kid = jwk.Thumbprint()
+	}
+	return jwk, nil
+}
+
+func rsaJWK(k *rsa.PublicKey) *JWK {
+	return &JWK{
+		Kty: "RSA",
+		N:   b64.EncodeToString(k.N.Bytes()),
+		E:   b64.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+	}
+}
+
+func ecJWK(k *ecdsa.PublicKey) (*JWK, error) {
+	crv := k.Curve.Params().Name
+	if curveByName(crv) == nil {
+		return nil, fmt.Errorf("unsupported curve %s", crv)
+	}
+	size := curveSize(k.Curve)
+	return &JWK{
+		Kty: "EC",
+		Crv: crv,
+		X:   b64.EncodeToString(k.X.FillBytes(make([]byte, size))),
+		Y:   b64.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+	}, nil
+}
+
+func curveByName(name string) elliptic.Curve {
+	switch name {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+func curveSize(c elliptic.Curve) int {
+	return (c.Params().BitSize + 7) / 8
+}
+
+// Key returns the Go key of the JWK: an *rsa.PrivateKey, *rsa.PublicKey, *ecdsa.PrivateKey,
+// *ecdsa.PublicKey, ed25519.PrivateKey, ed25519.PublicKey or []byte secret.
+func (k *JWK) Key() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("jwk: invalid RSA exponent")
+		}
+		pub := &rsa.PublicKey{N: n, E: int(e.Int64())}
+		if k.D == "" {
+			return pub, nil
+		}
+		d, err := decodeInt(k.D)
+		if err != nil {
+			return nil, err
+		}
+		p, err := decodeInt(k.P)
+		if err != nil {
+			return nil, err
+		}
+		q, err := decodeInt(k.Q)
+		if err != nil {
+			return nil, err
+		}
+		priv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+		if err := priv.Validate(); err != nil {
+			return nil, fmt.Errorf("jwk: %s", err)
+		}
+		priv.Precompute()
+		return priv, nil
+	case "EC":
+		curve := curveByName(k.Crv)
+		if curve == nil {
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("jwk: point is not on the curve")
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if k.D == "" {
+			return pub, nil
+		}
+		d, err := decodeInt(k.D)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PrivateKey{PublicKey: *pub, D: d}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwk: unsupported curve %q", k.Crv)
+		}
+		if k.D != "" {
+			seed, err := b64.DecodeString(k.D)
+			if err != nil || len(seed) != ed25519.SeedSize {
+				return nil, errors.New("jwk: invalid Ed25519 private key")
+			}
+			return ed25519.NewKeyFromSeed(seed), nil
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk: invalid Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		secret, err := b64.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("jwk: %s", err)
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("jwk: unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := b64.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("jwk: invalid integer member")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// Public returns a copy of the JWK without its private members.
+// Symmetric keys have no public form and return nil.
+func (k *JWK) Public() *JWK {
+	if k.Kty == "oct" {
+		return nil
+	}
+	return &JWK{
+		Kty: k.Kty, Kid: k.Kid, Use: k.Use, Alg: k.Alg, Crv: k.Crv,
+		N: k.N, E: k.E, X: k.X, Y: k.Y,
+	}
+}
+
+// Thumbprint returns the base64url-encoded RFC 7638 SHA-256 thumbprint of the key.
+func (k *JWK) Thumbprint() string {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{k.Crv, k.Kty, k.X, k.Y}
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	default:
+		members = struct {
+			K   string `json:"k"`
+			Kty string `json:"kty"`
+		}{k.K, k.Kty}
+	}
+	b, _ := json.Marshal(members)
+	sum := sha256.Sum256(b)
+	return b64.EncodeToString(sum[:])
+}
+
+// ParseJWKS parses a JSON Web Key Set.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	set := &JWKS{}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("jwks: %s", err)
+	}
+	return set, nil
+}
+
+// Public returns the set of the public keys of s, for publishing.
+func (s *JWKS) Public() *JWKS {
+	public := &JWKS{Keys: []*JWK{}}
+	for _, k := range s.Keys {
+		if pk := k.Public(); pk != nil {
+			public.Keys = append(public.Keys, pk)
+		}
+	}
+	return public
+}
+
+// Lookup returns the key with the given ID, or nil.
+func (s *JWKS) Lookup(kid string) *JWK {
+	for _, k := range s.Keys {
+		if k.Kid == kid {
+			return k
+		}
+	}
+	return nil
+}
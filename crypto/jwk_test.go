@@ -0,0 +1,84 @@
+package crypto_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+func TestJWKRoundTrip(t *testing.T) {
+	keys := testKeys(t)
+	for _, alg := range []string{crypto.HS256, crypto.RS256, crypto.ES256, crypto.EdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			jwk, err := crypto.NewJWK(keys[alg][0], "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if jwk.Kid != jwk.Thumbprint() {
+				t.Errorf("kid = %q, want thumbprint %q", jwk.Kid, jwk.Thumbprint())
+			}
+			b, err := json.Marshal(&crypto.JWKS{Keys: []*crypto.JWK{jwk}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			set, err := crypto.ParseJWKS(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := crypto.SignJWT(crypto.Claims{"sub": "alice"}, alg, set.Keys[0], jwk.Kid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify := set
+			if alg != crypto.HS256 {
+				verify = set.Public()
+				if verify.Keys[0].D != "" {
+					t.Error("public key has private member d")
+				}
+				if verify.Keys[0].Thumbprint() != jwk.Thumbprint() {
+					t.Error("public key has a different thumbprint")
+				}
+			} else if len(set.Public().Keys) != 0 {
+				t.Error("symmetric key is published")
+			}
+			if _, err := crypto.ParseJWT(token, verify.KeyFunc(), crypto.JWTValidation{}); err != nil {
+				t.Errorf("ParseJWT() = %v", err)
+			}
+		})
+	}
+}
+
+func TestJWKSKeyFunc(t *testing.T) {
+	keys := testKeys(t)
+	first, _ := crypto.NewJWK(keys[crypto.HS256][0], "first")
+	second, _ := crypto.NewJWK(keys[crypto.EdDSA][0], "second")
+	second.Alg = crypto.EdDSA
+	tests := []struct {
+		name    string
+		set     *crypto.JWKS
+		kid     string
+		alg     string
+		key     interface{}
+		wantErr error
+	}{
+		{"by kid", &crypto.JWKS{Keys: []*crypto.JWK{first, second}}, "second", crypto.EdDSA, keys[crypto.EdDSA][0], nil},
+		{"only key without kid", &crypto.JWKS{Keys: []*crypto.JWK{first}}, "", crypto.HS256, keys[crypto.HS256][0], nil},
+		{"no kid with several keys", &crypto.JWKS{Keys: []*crypto.JWK{first, second}}, "", crypto.HS256, keys[crypto.HS256][0], crypto.ErrJWTSignature},
+		{"unknown kid", &crypto.JWKS{Keys: []*crypto.JWK{first, second}}, "third", crypto.HS256, keys[crypto.HS256][0], crypto.ErrJWTSignature},
+		{"alg of key", &crypto.JWKS{Keys: []*crypto.JWK{first, second}}, "second", crypto.HS256, []byte("secret"), crypto.ErrJWTAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := crypto.SignJWT(crypto.Claims{}, tt.alg, tt.key, tt.kid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = crypto.ParseJWT(token, tt.set.KeyFunc(), crypto.JWTValidation{})
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// JWS algorithms supported by SignJWT and ParseJWT.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	EdDSA = "EdDSA"
+)
+
+// Errors returned by ParseJWT. Errors wrapping them carry details.
+var (
+	ErrJWTMalformed     = errors.New("jwt: malformed token")
+	ErrJWTAlgorithm     = errors.New("jwt: algorithm not allowed")
+	ErrJWTSignature     = errors.New("jwt: invalid signature")
+	ErrJWTExpired       = errors.New("jwt: token is expired")
+	ErrJWTNotYetValid   = errors.New("jwt: token is not valid yet")
+	ErrJWTInvalidClaims = errors.New("jwt: invalid claims")
+)
+
+// JWTHeader is the JOSE header of a token.
+type JWTHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// Claims is the claims set of a token. Numeric dates are seconds since the Unix epoch.
+type Claims map[string]interface{}
+
+// Subject returns the "sub" claim.
+func (c Claims) Subject() string {
+	s, _ := c["sub"].(string)
+	return s
+}
+
+// Issuer returns the "iss" claim.
+func (c Claims) Issuer() string {
+	s, _ := c["iss"].(string)
+	return s
+}
+
+// Audience returns the "aud" claim, which may be a string or a list of strings.
+func (c Claims) Audience() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []string:
+		return aud
+	case []interface{}:
+		list := make([]string, 0, len(aud))
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// ExpiresAt returns the "exp" claim, or the zero time if it is missing.
+func (c Claims) ExpiresAt() time.Time {
+	return c.time("exp")
+}
+
+// NotBefore returns the "nbf" claim, or the zero time if it is missing.
+func (c Claims) NotBefore() time.Time {
+	return c.time("nbf")
+}
+
+// IssuedAt returns the "iat" claim, or the zero time if it is missing.
+func (c Claims) IssuedAt() time.Time {
+	return c.time("iat")
+}
+
+func (c Claims) time(name string) time.Time {
+	var sec float64
+	switch v := c[name].(type) {
+	case float64:
+		sec = v
+	case int64:
+		sec = float64(v)
+	case int:
+		sec = float64(v)
+	case json.Number:
+		sec, _ = v.Float64()
+	case time.Time:
+		return v
+	default:
+		return time.Time{}
+	}
+	return time.Unix(0, int64(sec*float64(time.Second)))
+}
+
+// JWTKeyFunc returns the key verifying a token with the given header.
+type JWTKeyFunc func(header JWTHeader) (interface{}, error)
+
+// StaticJWTKey returns a JWTKeyFunc always returning key.
+func StaticJWTKey(key interface{}) JWTKeyFunc {
+	return func(JWTHeader) (interface{}, error) {
+		return key, nil
+	}
+}
+
+// KeyFunc returns a JWTKeyFunc looking up keys in s by the kid of the token.
+// Tokens without a kid are verified with the only key of s, if it has exactly one.
+func (s *JWKS) KeyFunc() JWTKeyFunc {
+	return func(header JWTHeader) (interface{}, error) {
+		var k *JWK
+		switch {
+		case header.Kid != "":
+			k = s.Lookup(header.Kid)
+		case len(s.Keys) == 1:
+			k = s.Keys[0]
+		}
+		if k == nil {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrJWTSignature, header.Kid)
+		}
+		if k.Alg != "" && k.Alg != header.Alg {
+			return nil, fmt.Errorf("%w: key %q is for %s", ErrJWTAlgorithm, k.Kid, k.Alg)
+		}
+		return k.Key()
+	}
+}
+
+// JWTValidation configures the checks of ParseJWT beyond the signature.
+// Issuer and Audience are checked if set. Leeway is the clock skew allowed
+// for the exp, nbf and iat claims. Algorithms restricts the accepted algorithms;
+// the algorithm must always match the type of the key.
+type JWTValidation struct {
+	Algorithms []string
+	Issuer     string
+	Audience   string
+	Leeway     time.Duration
+	RequireExp bool
+	Now        func() time.Time
+}
+
+// SignJWT returns a compact JWS of claims signed with key using alg. key is a []byte
+// secret for HS256, or an *rsa.PrivateKey, *ecdsa.PrivateKey on P-256, ed25519.PrivateKey
+// or *JWK for the other algorithms. kid, if not empty, is set in the header.
+func SignJWT(claims Claims, alg string, key interface{}, kid string) (string, error) {
+	if jwk, ok := key.(*JWK); ok {
+		k, err := jwk.Key()
+		if err != nil {
+			return "", err
+		}
+		key = k
+	}
+	header, err := json.Marshal(JWTHeader{Alg: alg, Typ: "JWT", Kid: kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwt: %s", err)
+	}
+	input := b64.EncodeToString(header) + "." + b64.EncodeToString(payload)
+	sig, err := signJWS(alg, key, []byte(input))
+	if err != nil {
+		return "", err
+	}
+	return input + "." + b64.EncodeToString(sig), nil
+}
+
+// ParseJWT verifies the signature of token with the key returned by keys,
+// validates its claims and returns them.
+func ParseJWT(token string, keys JWTKeyFunc, v JWTValidation) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrJWTMalformed
+	}
+	var header JWTHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if len(v.Algorithms) > 0 && !contains(v.Algorithms, header.Alg) {
+		return nil, fmt.Errorf("%w: %q", ErrJWTAlgorithm, header.Alg)
+	}
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrJWTMalformed
+	}
+	key, err := keys(header)
+	if err != nil {
+		return nil, err
+	}
+	if jwk, ok := key.(*JWK); ok {
+		if key, err = jwk.Key(); err != nil {
+			return nil, err
+		}
+	}
+	if err := verifyJWS(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return nil, err
+	}
+
+	claims := Claims{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v JWTValidation) validate(c Claims) error {
+	now := time.Now()
+	if v.Now != nil {
+		now = v.Now()
+	}
+	exp := c.ExpiresAt()
+	if exp.IsZero() && v.RequireExp {
+		return fmt.Errorf("%w: missing exp", ErrJWTInvalidClaims)
+	}
+	if !exp.IsZero() && !now.Before(exp.Add(v.Leeway)) {
+		return ErrJWTExpired
+	}
+	if nbf := c.NotBefore(); !nbf.IsZero() && now.Add(v.Leeway).Before(nbf) {
+		return ErrJWTNotYetValid
+	}
+	if iat := c.IssuedAt(); !iat.IsZero() && now.Add(v.Leeway).Before(iat) {
+		return ErrJWTNotYetValid
+	}
+	if v.Issuer != "" && c.Issuer() != v.Issuer {
+		return fmt.Errorf("%w: issuer %q", ErrJWTInvalidClaims, c.Issuer())
+	}
+	if v.Audience != "" && !contains(c.Audience(), v.Audience) {
+		return fmt.Errorf("%w: audience %q", ErrJWTInvalidClaims, c.Audience())
+	}
+	return nil
+}
+
+func signJWS(alg string, key interface{}, input []byte) ([]byte, error) {
+	switch alg {
+	case HS256:
+		if k, ok := key.([]byte); ok {
+			return HmacSha256(k, input), nil
+		}
+	case RS256:
+		if k, ok := key.(*rsa.PrivateKey); ok {
+			digest := sha256.Sum256(input)
+			return rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		}
+	case ES256:
+		if k, ok := key.(*ecdsa.PrivateKey); ok && k.Curve == elliptic.P256() {
+			digest := sha256.Sum256(input)
+			r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+			if err != nil {
+				return nil, err
+			}
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+			return sig, nil
+		}
+	case EdDSA:
+		if k, ok := key.(ed25519.PrivateKey); ok {
+			return ed25519.Sign(k, input), nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrJWTAlgorithm, alg)
+	}
+	return nil, fmt.Errorf("jwt: key of type %T cannot sign %s", key, alg)
+}
+
+func verifyJWS(alg string, key interface{}, input, sig []byte) error {
+	valid := false
+	switch alg {
+	case HS256:
+		k, ok := key.([]byte)
+		if !ok {
+			return fmt.Errorf("%w: %s requires a secret key", ErrJWTAlgorithm, alg)
+		}
+		valid = hmac.Equal(sig, HmacSha256(k, input))
+	case RS256:
+		var pub *rsa.PublicKey
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			pub = k
+		case *rsa.PrivateKey:
+			pub = &k.PublicKey
+		default:
+			return fmt.Errorf("%w: %s requires an RSA key", ErrJWTAlgorithm, alg)
+		}
+		digest := sha256.Sum256(input)
+		valid = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+	case ES256:
+		var pub *ecdsa.PublicKey
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			pub = k
+		case *ecdsa.PrivateKey:
+			pub = &k.PublicKey
+		}
+		if pub == nil || pub.Curve != elliptic.P256() {
+			return fmt.Errorf("%w: %s requires a P-256 key", ErrJWTAlgorithm, alg)
+		}
+		if len(sig) == 64 {
+			digest := sha256.Sum256(input)
+			r := new(big.Int).SetBytes(sig[:32])
+			s := new(big.Int).SetBytes(sig[32:])
+			valid = ecdsa.Verify(pub, digest[:], r, s)
+		}
+	case EdDSA:
+		var pub ed25519.PublicKey
+		switch k := key.(type) {
+		case ed25519.PublicKey:
+			pub = k
+		case ed25519.PrivateKey:
+			pub = k.Public().(ed25519.PublicKey)
+		default:
+			return fmt.Errorf("%w: %s requires an Ed25519 key", ErrJWTAlgorithm, alg)
+		}
+		valid = len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, input, sig)
+	default:
+		return fmt.Errorf("%w: %q", ErrJWTAlgorithm, alg)
+	}
+	if !valid {
+		return ErrJWTSignature
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := b64.DecodeString(seg)
+	if err != nil {
+		return ErrJWTMalformed
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("%w: %s", ErrJWTMalformed, err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
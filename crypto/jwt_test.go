@@ -0,0 +1,204 @@
+package crypto_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+// testKeys returns a signing key and the matching verification key for each algorithm.
+func testKeys(t *testing.T) map[string][2]interface{} {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	return map[string][2]interface{}{
+		crypto.HS256: {secret, secret},
+		crypto.RS256: {rsaKey, &rsaKey.PublicKey},
+		crypto.ES256: {ecKey, &ecKey.PublicKey},
+		crypto.EdDSA: {edKey, edPub},
+	}
+}
+
+func TestJWTSignVerify(t *testing.T) {
+	keys := testKeys(t)
+	for _, alg := range []string{crypto.HS256, crypto.RS256, crypto.ES256, crypto.EdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			token, err := crypto.SignJWT(crypto.Claims{"sub": "alice"}, alg, keys[alg][0], "k1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			claims, err := crypto.ParseJWT(token, crypto.StaticJWTKey(keys[alg][1]), crypto.JWTValidation{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Subject() != "alice" {
+				t.Errorf("sub = %q, want alice", claims.Subject())
+			}
+
+			parts := strings.Split(token, ".")
+			tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"mallory"}`)) + "." + parts[2]
+			if _, err := crypto.ParseJWT(tampered, crypto.StaticJWTKey(keys[alg][1]), crypto.JWTValidation{}); !errors.Is(err, crypto.ErrJWTSignature) {
+				t.Errorf("tampered payload: err = %v, want %v", err, crypto.ErrJWTSignature)
+			}
+		})
+	}
+}
+
+func TestJWTAlgorithmMismatch(t *testing.T) {
+	keys := testKeys(t)
+	rsaPub := keys[crypto.RS256][1].(*rsa.PublicKey)
+	tests := []struct {
+		name    string
+		alg     string
+		signKey interface{}
+		key     interface{}
+		allowed []string
+	}{
+		{"HS256 with RSA key", crypto.HS256, []byte("secret"), rsaPub, nil},
+		{"RS256 with secret", crypto.RS256, keys[crypto.RS256][0], []byte("secret"), nil},
+		{"RS256 with EC key", crypto.RS256, keys[crypto.RS256][0], keys[crypto.ES256][1], nil},
+		{"ES256 with Ed25519 key", crypto.ES256, keys[crypto.ES256][0], keys[crypto.EdDSA][1], nil},
+		{"EdDSA with RSA key", crypto.EdDSA, keys[crypto.EdDSA][0], rsaPub, nil},
+		{"algorithm not allowed", crypto.HS256, []byte("secret"), []byte("secret"), []string{crypto.RS256}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := crypto.SignJWT(crypto.Claims{"sub": "alice"}, tt.alg, tt.signKey, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = crypto.ParseJWT(token, crypto.StaticJWTKey(tt.key), crypto.JWTValidation{Algorithms: tt.allowed})
+			if !errors.Is(err, crypto.ErrJWTAlgorithm) {
+				t.Errorf("err = %v, want %v", err, crypto.ErrJWTAlgorithm)
+			}
+		})
+	}
+}
+
+func TestJWTSignKeyMismatch(t *testing.T) {
+	keys := testKeys(t)
+	tests := []struct {
+		alg string
+		key interface{}
+	}{
+		{crypto.HS256, keys[crypto.RS256][0]},
+		{crypto.RS256, keys[crypto.ES256][0]},
+		{crypto.ES256, keys[crypto.EdDSA][0]},
+		{crypto.EdDSA, []byte("secret")},
+		{"none", nil},
+	}
+	for _, tt := range tests {
+		if _, err := crypto.SignJWT(crypto.Claims{}, tt.alg, tt.key, ""); err == nil {
+			t.Errorf("SignJWT(%s, %T) succeeded, want error", tt.alg, tt.key)
+		}
+	}
+}
+
+func TestJWTNone(t *testing.T) {
+	enc := base64.RawURLEncoding.EncodeToString
+	payload := enc([]byte(`{"sub":"admin"}`))
+	for _, header := range []string{`{"alg":"none"}`, `{"alg":"None"}`, `{"alg":""}`} {
+		t.Run(header, func(t *testing.T) {
+			for _, token := range []string{enc([]byte(header)) + "." + payload + ".", enc([]byte(header)) + "." + payload + "." + enc([]byte("x"))} {
+				_, err := crypto.ParseJWT(token, crypto.StaticJWTKey([]byte("secret")), crypto.JWTValidation{})
+				if !errors.Is(err, crypto.ErrJWTAlgorithm) {
+					t.Errorf("err = %v, want %v", err, crypto.ErrJWTAlgorithm)
+				}
+			}
+		})
+	}
+}
+
+func TestJWTMalformed(t *testing.T) {
+	secret := []byte("secret")
+	valid, err := crypto.SignJWT(crypto.Claims{"sub": "alice"}, crypto.HS256, secret, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(valid, ".")
+	enc := base64.RawURLEncoding.EncodeToString
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{"empty", "", crypto.ErrJWTMalformed},
+		{"two segments", parts[0] + "." + parts[1], crypto.ErrJWTMalformed},
+		{"four segments", valid + ".x", crypto.ErrJWTMalformed},
+		{"header not base64", "!!." + parts[1] + "." + parts[2], crypto.ErrJWTMalformed},
+		{"header not JSON", enc([]byte("alg")) + "." + parts[1] + "." + parts[2], crypto.ErrJWTMalformed},
+		{"signature not base64", parts[0] + "." + parts[1] + ".!!", crypto.ErrJWTMalformed},
+		// The signature is checked before the payload is decoded.
+		{"payload not base64", parts[0] + ".!!." + parts[2], crypto.ErrJWTSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := crypto.ParseJWT(tt.token, crypto.StaticJWTKey(secret), crypto.JWTValidation{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWTValidation(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Unix(1700000000, 0)
+	at := func(d time.Duration) int64 { return now.Add(d).Unix() }
+	tests := []struct {
+		name    string
+		claims  crypto.Claims
+		v       crypto.JWTValidation
+		wantErr error
+	}{
+		{"valid", crypto.Claims{"exp": at(time.Minute), "nbf": at(-time.Minute)}, crypto.JWTValidation{}, nil},
+		{"expired", crypto.Claims{"exp": at(-time.Second)}, crypto.JWTValidation{}, crypto.ErrJWTExpired},
+		{"expired now", crypto.Claims{"exp": at(0)}, crypto.JWTValidation{}, crypto.ErrJWTExpired},
+		{"expired within leeway", crypto.Claims{"exp": at(-20 * time.Second)}, crypto.JWTValidation{Leeway: 30 * time.Second}, nil},
+		{"expired beyond leeway", crypto.Claims{"exp": at(-40 * time.Second)}, crypto.JWTValidation{Leeway: 30 * time.Second}, crypto.ErrJWTExpired},
+		{"not yet valid", crypto.Claims{"nbf": at(time.Second)}, crypto.JWTValidation{}, crypto.ErrJWTNotYetValid},
+		{"nbf within leeway", crypto.Claims{"nbf": at(20 * time.Second)}, crypto.JWTValidation{Leeway: 30 * time.Second}, nil},
+		{"nbf beyond leeway", crypto.Claims{"nbf": at(40 * time.Second)}, crypto.JWTValidation{Leeway: 30 * time.Second}, crypto.ErrJWTNotYetValid},
+		{"issued in the future", crypto.Claims{"iat": at(time.Minute)}, crypto.JWTValidation{}, crypto.ErrJWTNotYetValid},
+		{"missing exp", crypto.Claims{}, crypto.JWTValidation{RequireExp: true}, crypto.ErrJWTInvalidClaims},
+		{"issuer", crypto.Claims{"iss": "https://issuer"}, crypto.JWTValidation{Issuer: "https://issuer"}, nil},
+		{"wrong issuer", crypto.Claims{"iss": "https://other"}, crypto.JWTValidation{Issuer: "https://issuer"}, crypto.ErrJWTInvalidClaims},
+		{"audience string", crypto.Claims{"aud": "api"}, crypto.JWTValidation{Audience: "api"}, nil},
+		{"audience list", crypto.Claims{"aud": []string{"web", "api"}}, crypto.JWTValidation{Audience: "api"}, nil},
+		{"audience not in list", crypto.Claims{"aud": []string{"web", "admin"}}, crypto.JWTValidation{Audience: "api"}, crypto.ErrJWTInvalidClaims},
+		{"missing audience", crypto.Claims{}, crypto.JWTValidation{Audience: "api"}, crypto.ErrJWTInvalidClaims},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := crypto.SignJWT(tt.claims, crypto.HS256, secret, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.v.Now = func() time.Time { return now }
+			_, err = crypto.ParseJWT(token, crypto.StaticJWTKey(secret), tt.v)
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v2"
 	"io"
 	"io/ioutil"
@@ -105,6 +106,7 @@ var DefaultDecoders = DecoderGroup{
 	"xml":  DecoderMakerFunc(func(r io.Reader) Decoder { return xml.NewDecoder(r) }),
 	"json": DecoderMakerFunc(func(r io.Reader) Decoder { return json.NewDecoder(r) }),
 	"yaml": DecoderMakerFunc(func(r io.Reader) Decoder { return &yamlDecoder{r} }),
+	"toml": DecoderMakerFunc(func(r io.Reader) Decoder { return &tomlDecoder{r} }),
 }
 
 type (
@@ -143,6 +145,15 @@ func (yd *yamlDecoder) Decode(v interface{}) error {
 	return yaml.Unmarshal(b, v)
 }
 
+type tomlDecoder struct {
+	r io.Reader
+}
+
+func (td *tomlDecoder) Decode(v interface{}) error {
+	_, err := toml.NewDecoder(td.r).Decode(v)
+	return err
+}
+
 func ReadAsCSV(val string) ([]string, error) {
 	if val == "" {
 		return []string{}, nil
@@ -0,0 +1,357 @@
+package flag
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/robfig/cron/v3"
+
+	"github.com/gofunct/functional/flag/cidrflag"
+	"github.com/gofunct/functional/flag/constflag"
+	"github.com/gofunct/functional/flag/driver"
+	"github.com/gofunct/functional/flag/fileflag"
+	"github.com/gofunct/functional/flag/mapflag"
+	"github.com/gofunct/functional/flag/netflag"
+	"github.com/gofunct/functional/flag/timeflag"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// Binding ties the tagged fields of a config struct to the flags registered for them.
+type Binding struct {
+	FlagSet *flag.FlagSet
+
+	fields []boundField
+}
+
+type boundField struct {
+	name  string
+	group string
+	value flag.Value
+	apply func()
+}
+
+// Bind registers a flag on fs for every field of the struct pointed to by v that has a `flag` tag,
+// choosing the flag value type from the field type. The current field values are used as defaults.
+//
+// The following struct tags are recognized:
+//
+//  flag:"name"        the flag name; "-" skips the field, an empty name uses the lower-cased field name
+//  help:"..."         the usage string
+//  choices:"a,b"      restricts string and []string fields to the given choices
+//  sep:","            separator for list fields (CSV) and for KEY=VALUE map fields
+//  accumulate:"true"  accumulates the values of repeated CSV list flags instead of keeping the last list
+//  layout:"..."       time layout for time.Time fields
+//  group:"..."        the group the flag is listed under by `Usage`
+//
+// Tagged struct fields that are not themselves flag values are walked recursively,
+// with their flag names prefixed by the parent's name and a dash.
+//
+// Example:
+//
+//  var config struct {
+//  	Fruit    string            `flag:"fruit" help:"a fruit" choices:"apple,banana"`
+//  	URLs     []*url.URL        `flag:"url" help:"a URL"`
+//  	Settings map[string]string `flag:"set" help:"set key=value"`
+//  }
+//  binding, err := flag.Bind(fs, &config)
+//  if err != nil {
+//  	return err
+//  }
+//  err = binding.Parse(os.Args[1:])
+func Bind(fs *flag.FlagSet, v interface{}) (*Binding, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("flag: Bind requires a pointer to a struct, got %T", v)
+	}
+	b := &Binding{FlagSet: fs}
+	if err := b.bindStruct(rv.Elem(), ""); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Parse parses args using the flag set and copies the results into the bound struct.
+func (b *Binding) Parse(args []string) error {
+	if err := b.FlagSet.Parse(args); err != nil {
+		return err
+	}
+	b.Apply()
+	return nil
+}
+
+// Apply copies the values of all changed flags into their struct fields.
+// Fields of flags that were not set keep their default values.
+func (b *Binding) Apply() {
+	for _, f := range b.fields {
+		if f.apply == nil {
+			continue
+		}
+		if changed, ok := f.value.(interface {
+			HasChanged() bool
+		}); ok && !changed.HasChanged() {
+			continue
+		}
+		f.apply()
+	}
+}
+
+// Lookup returns the flag value bound under name, or nil.
+func (b *Binding) Lookup(name string) flag.Value {
+	for _, f := range b.fields {
+		if f.name == name {
+			return f.value
+		}
+	}
+	return nil
+}
+
+// Groups returns the `group` tags of the bound flags by flag name, for use as `Usage.Groups`.
+func (b *Binding) Groups() map[string]string {
+	groups := make(map[string]string)
+	for _, f := range b.fields {
+		if f.group != "" {
+			groups[f.name] = f.group
+		}
+	}
+	return groups
+}
+
+func (b *Binding) bindStruct(rv reflect.Value, prefix string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		name, ok := sf.Tag.Lookup("flag")
+		if !ok || name == "-" || sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		name = prefix + name
+		field := rv.Field(i)
+		if _, isValue := field.Addr().Interface().(flag.Value); !isValue && sf.Type.Kind() == reflect.Struct && !isLeafStruct(sf.Type) {
+			if err := b.bindStruct(field, name+"-"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := b.bindField(field, name, sf.Tag); err != nil {
+			return fmt.Errorf("flag: field %s: %v", sf.Name, err)
+		}
+	}
+	return nil
+}
+
+func isLeafStruct(t reflect.Type) bool {
+	return t == reflect.TypeOf(time.Time{}) || t == reflect.TypeOf(url.URL{})
+}
+
+func (b *Binding) bindField(field reflect.Value, name string, tag reflect.StructTag) error {
+	fs := b.FlagSet
+	usage := tag.Get("help")
+	sep := tag.Get("sep")
+	accumulate := tag.Get("accumulate") == "true"
+	var choices []string
+	if c := tag.Get("choices"); c != "" {
+		for _, choice := range strings.Split(c, ",") {
+			choices = append(choices, strings.TrimSpace(choice))
+		}
+	}
+
+	if fs.Lookup(name) != nil {
+		return fmt.Errorf("flag -%s is already defined", name)
+	}
+
+	var value flag.Value
+	var apply func()
+	switch p := field.Addr().Interface().(type) {
+	case flag.Value:
+		value = p
+	case *string:
+		if len(choices) == 0 {
+			fs.StringVar(p, name, *p, usage)
+			break
+		}
+		fv := &constflag.Enum{Choices: choices, Default: *p, Value: *p}
+		value, apply = fv, func() { *p = fv.Value }
+	case *bool:
+		fs.BoolVar(p, name, *p, usage)
+	case *int:
+		fs.IntVar(p, name, *p, usage)
+	case *int64:
+		fs.Int64Var(p, name, *p, usage)
+	case *uint:
+		fs.UintVar(p, name, *p, usage)
+	case *uint64:
+		fs.Uint64Var(p, name, *p, usage)
+	case *float64:
+		fs.Float64Var(p, name, *p, usage)
+	case *time.Duration:
+		fs.DurationVar(p, name, *p, usage)
+	case *[]string:
+		switch {
+		case len(choices) > 0 && sep != "":
+			fv := &constflag.EnumsCSV{Choices: choices, Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		case len(choices) > 0:
+			fv := &constflag.Enums{Choices: choices}
+			value, apply = fv, func() { *p = fv.Values }
+		case sep != "":
+			fv := &typeflag.StringsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		default:
+			fv := &typeflag.Strings{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *[]int64:
+		if sep != "" {
+			fv := &typeflag.IntsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &typeflag.Ints{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *[]uint64:
+		if sep != "" {
+			fv := &typeflag.UintsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &typeflag.Uints{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *[]float64:
+		if sep != "" {
+			fv := &typeflag.FloatsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &typeflag.Floats{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *net.IP:
+		fv := &netflag.IP{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]net.IP:
+		if sep != "" {
+			fv := &netflag.IPsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &netflag.IPs{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case **url.URL:
+		fv := &netflag.URL{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *url.URL:
+		fv := &netflag.URL{}
+		value, apply = fv, func() { *p = *fv.Value }
+	case *[]*url.URL:
+		fv := &netflag.URLs{}
+		value, apply = fv, func() { *p = fv.Values }
+	case **net.TCPAddr:
+		fv := &netflag.TCPAddr{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]*net.TCPAddr:
+		if sep != "" {
+			fv := &netflag.TCPAddrsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &netflag.TCPAddrs{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case **net.UDPAddr:
+		fv := &netflag.UDPAddr{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]*net.UDPAddr:
+		if sep != "" {
+			fv := &netflag.UDPAddrsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &netflag.UDPAddrs{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *net.HardwareAddr:
+		fv := &netflag.HardwareAddr{}
+		value, apply = fv, func() { *p = fv.Value }
+	case **net.IPNet:
+		fv := &cidrflag.CIDR{}
+		value, apply = fv, func() { *p = fv.Value.IPNet }
+	case *[]*net.IPNet:
+		if sep != "" {
+			fv := &cidrflag.CIDRsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Prefixes.Prefixes() }
+		} else {
+			fv := &cidrflag.CIDRs{}
+			value, apply = fv, func() { *p = fv.Prefixes.Prefixes() }
+		}
+	case *map[string]string:
+		fv := &mapflag.AssignmentsMap{Separator: sep}
+		value, apply = fv, func() {
+			if *p == nil {
+				*p = make(map[string]string)
+			}
+			for k, v := range fv.Values {
+				(*p)[k] = v
+			}
+		}
+	case *map[string]interface{}:
+		fv := &mapflag.NestedMap{Separator: sep}
+		value, apply = fv, func() { *p = fv.Merge(*p) }
+	case *glob.Glob:
+		fv := &fileflag.Glob{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]glob.Glob:
+		fv := &fileflag.Globs{}
+		value, apply = fv, func() { *p = fv.Values }
+	case **template.Template:
+		fv := &fileflag.Template{Root: *p}
+		value, apply = fv, func() { *p = fv.Value }
+	case **regexp.Regexp:
+		fv := &typeflag.Regexp{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]*regexp.Regexp:
+		fv := &typeflag.Regexps{}
+		value, apply = fv, func() { *p = fv.Values }
+	case *time.Time:
+		fv := &timeflag.Time{}
+		if layout := tag.Get("layout"); layout != "" {
+			fv.Layouts = []string{layout}
+		}
+		value, apply = fv, func() { *p = fv.Value }
+	case *[]time.Time:
+		fv := &timeflag.Times{}
+		if layout := tag.Get("layout"); layout != "" {
+			fv.Layouts = []string{layout}
+		}
+		value, apply = fv, func() { *p = fv.Values }
+	case *[]time.Duration:
+		if sep != "" {
+			fv := &timeflag.DurationsCSV{Separator: sep, Accumulate: accumulate}
+			value, apply = fv, func() { *p = fv.Values }
+		} else {
+			fv := &timeflag.Durations{}
+			value, apply = fv, func() { *p = fv.Values }
+		}
+	case *cron.Schedule:
+		fv := &timeflag.Cron{}
+		value, apply = fv, func() { *p = fv.Value }
+	case *interface{}:
+		fv := &typeflag.JSON{}
+		value, apply = fv, func() { *p = fv.Value }
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	if value != nil {
+		driver.Var(fs, value, name, usage)
+	}
+	b.fields = append(b.fields, boundField{name: name, group: tag.Get("group"), value: fs.Lookup(name).Value, apply: apply})
+	return nil
+}
@@ -0,0 +1,293 @@
+package flag_test
+
+import (
+	goflag "flag"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gofunct/functional/flag"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestBindTypes(t *testing.T) {
+	var config struct {
+		String    string            `flag:"string"`
+		Enum      string            `flag:"enum" choices:"a, b"`
+		Bool      bool              `flag:"bool"`
+		Int       int               `flag:"int"`
+		Int64     int64             `flag:"int64"`
+		Uint      uint              `flag:"uint"`
+		Uint64    uint64            `flag:"uint64"`
+		Float     float64           `flag:"float"`
+		Duration  time.Duration     `flag:"duration"`
+		Strings   []string          `flag:"strings"`
+		StrCSV    []string          `flag:"strcsv" sep:";"`
+		Enums     []string          `flag:"enums" choices:"a,b"`
+		EnumsCSV  []string          `flag:"enumscsv" choices:"a,b" sep:","`
+		Ints      []int64           `flag:"ints"`
+		IntsCSV   []int64           `flag:"intscsv" sep:","`
+		Uints     []uint64          `flag:"uints"`
+		Floats    []float64         `flag:"floatscsv" sep:","`
+		IP        net.IP            `flag:"ip"`
+		IPs       []net.IP          `flag:"ips" sep:","`
+		URLPtr    *url.URL          `flag:"urlptr"`
+		URL       url.URL           `flag:"url"`
+		URLs      []*url.URL        `flag:"urls"`
+		TCP       *net.TCPAddr      `flag:"tcp"`
+		UDPs      []*net.UDPAddr    `flag:"udps" sep:","`
+		MAC       net.HardwareAddr  `flag:"mac"`
+		CIDR      *net.IPNet        `flag:"cidr"`
+		Map       map[string]string `flag:"map"`
+		Regexp    *regexp.Regexp    `flag:"regexp"`
+		Time      time.Time         `flag:"time" layout:"2006-01-02"`
+		Durations []time.Duration   `flag:"durations" sep:","`
+		JSON      interface{}       `flag:"json"`
+		Value     typeflag.Strings  `flag:"value"`
+	}
+	b, err := flag.Bind(goflag.NewFlagSet("test", goflag.ContinueOnError), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = b.Parse([]string{
+		"-string", "s", "-enum", "B", "-bool", "-int", "-1", "-int64", "2", "-uint", "3", "-uint64", "4",
+		"-float", "0.5", "-duration", "1m", "-strings", "x", "-strings", "y", "-strcsv", "x; y",
+		"-enums", "a", "-enums", "b", "-enumscsv", "b,a", "-ints", "1", "-ints", "2", "-intscsv", "3,4",
+		"-uints", "5", "-floatscsv", "0.5,1", "-ip", "10.0.0.1", "-ips", "10.0.0.2,::1",
+		"-urlptr", "https://a.example/", "-url", "https://b.example/x", "-urls", "https://c.example/",
+		"-tcp", "127.0.0.1:80", "-udps", "127.0.0.1:53", "-mac", "00:00:5e:00:53:01", "-cidr", "10.0.0.0/8",
+		"-map", "k=v", "-regexp", "^a+$", "-time", "2020-01-02", "-durations", "1s,2s", "-json", `{"a":[1]}`,
+		"-value", "v",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want string
+	}{
+		{"string", config.String, "s"},
+		{"enum", config.Enum, "b"},
+		{"bool", config.Bool, "true"},
+		{"int", config.Int, "-1"},
+		{"int64", config.Int64, "2"},
+		{"uint", config.Uint, "3"},
+		{"uint64", config.Uint64, "4"},
+		{"float", config.Float, "0.5"},
+		{"duration", config.Duration, "1m0s"},
+		{"strings", config.Strings, "[x y]"},
+		{"strcsv", config.StrCSV, "[x y]"},
+		{"enums", config.Enums, "[a b]"},
+		{"enumscsv", config.EnumsCSV, "[b a]"},
+		{"ints", config.Ints, "[1 2]"},
+		{"intscsv", config.IntsCSV, "[3 4]"},
+		{"uints", config.Uints, "[5]"},
+		{"floatscsv", config.Floats, "[0.5 1]"},
+		{"ip", config.IP, "10.0.0.1"},
+		{"ips", config.IPs, "[10.0.0.2 ::1]"},
+		{"urlptr", config.URLPtr, "https://a.example/"},
+		{"url", config.URL.String(), "https://b.example/x"},
+		{"urls", config.URLs, "[https://c.example/]"},
+		{"tcp", config.TCP, "127.0.0.1:80"},
+		{"udps", config.UDPs, "[127.0.0.1:53]"},
+		{"mac", config.MAC, "00:00:5e:00:53:01"},
+		{"cidr", config.CIDR, "10.0.0.0/8"},
+		{"map", config.Map, "map[k:v]"},
+		{"regexp", config.Regexp, "^a+$"},
+		{"time", config.Time.Format(time.RFC3339), "2020-01-02T00:00:00Z"},
+		{"durations", config.Durations, "[1s 2s]"},
+		{"json", config.JSON, "map[a:[1]]"},
+		{"value", config.Value.Values, "[v]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(tt.got); got != tt.want {
+			t.Errorf("-%s = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBindTags(t *testing.T) {
+	type Inner struct {
+		Port int `flag:"port" help:"the port" group:"Server"`
+	}
+	var config struct {
+		Name     string `flag:"" help:"the name"`
+		Skipped  string `flag:"-"`
+		Untagged string
+		hidden   string `flag:"hidden"`
+		Server   Inner  `flag:"server"`
+		Embedded struct {
+			Inner `flag:"inner"`
+		} `flag:"outer"`
+	}
+	fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+	b, err := flag.Bind(fs, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	fs.VisitAll(func(f *goflag.Flag) {
+		names = append(names, f.Name+": "+f.Usage)
+	})
+	want := []string{"name: the name", "outer-inner-port: the port", "server-port: the port"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("flags = %q, want %q", names, want)
+	}
+	wantGroups := map[string]string{"server-port": "Server", "outer-inner-port": "Server"}
+	if groups := b.Groups(); !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("Groups = %v, want %v", groups, wantGroups)
+	}
+	if b.Lookup("server-port") == nil || b.Lookup("skipped") != nil {
+		t.Error("Lookup does not report exactly the bound flags")
+	}
+	if err := b.Parse([]string{"-server-port", "80"}); err != nil {
+		t.Fatal(err)
+	}
+	if config.Server.Port != 80 || config.Embedded.Port != 0 || config.hidden != "" {
+		t.Errorf("config = %+v", config)
+	}
+}
+
+func TestBindCSVAccumulate(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"replace", []string{"-replace", "a,b", "-replace", "c"}, "[c] [] [] []"},
+		{"accumulate", []string{"-accumulate", "a,b", "-accumulate", "c"}, "[] [a b c] [] []"},
+		{"replace ints", []string{"-ints", "1,2", "-ints", "3"}, "[] [] [3] []"},
+		{"accumulate ints", []string{"-ints-acc", "1,2", "-ints-acc", "3"}, "[] [] [] [1 2 3]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config struct {
+				Replace    []string `flag:"replace" sep:","`
+				Accumulate []string `flag:"accumulate" sep:"," accumulate:"true"`
+				Ints       []int64  `flag:"ints" sep:","`
+				IntsAcc    []int64  `flag:"ints-acc" sep:"," accumulate:"true"`
+			}
+			b, err := flag.Bind(goflag.NewFlagSet("test", goflag.ContinueOnError), &config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got := fmt.Sprint(config.Replace, config.Accumulate, config.Ints, config.IntsAcc)
+			if got != tt.want {
+				t.Errorf("values = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindDefaults(t *testing.T) {
+	config := struct {
+		Name    string   `flag:"name"`
+		Level   string   `flag:"level" choices:"debug,info"`
+		Tags    []string `flag:"tags"`
+		Timeout time.Duration
+		Ints    []int64 `flag:"ints" sep:","`
+	}{Name: "default", Level: "info", Tags: []string{"a"}, Ints: []int64{1}}
+	fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+	b, err := flag.Bind(fs, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fs.Lookup("name").DefValue; got != "default" {
+		t.Errorf("-name default = %q, want default", got)
+	}
+	if err := b.Parse([]string{"-name", "set"}); err != nil {
+		t.Fatal(err)
+	}
+	if config.Name != "set" || config.Level != "info" || !reflect.DeepEqual(config.Tags, []string{"a"}) || !reflect.DeepEqual(config.Ints, []int64{1}) {
+		t.Errorf("config = %+v, want unset flags to keep their defaults", config)
+	}
+
+	// Apply copies flags changed after Parse, such as by a Resolver.
+	if err := fs.Set("level", "debug"); err != nil {
+		t.Fatal(err)
+	}
+	if config.Level != "info" {
+		t.Fatal("Set changed the field before Apply")
+	}
+	b.Apply()
+	if config.Level != "debug" {
+		t.Errorf("Level = %q after Apply, want debug", config.Level)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	type Inner struct {
+		Port int `flag:"port"`
+	}
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"not a pointer", struct{}{}, "flag: Bind requires a pointer to a struct, got struct {}"},
+		{"not a struct", new(int), "flag: Bind requires a pointer to a struct, got *int"},
+		{"unsupported type", &struct {
+			C chan int `flag:"c"`
+		}{}, "flag: field C: unsupported type chan int"},
+		{"duplicate name", &struct {
+			Port  int   `flag:"port"`
+			Other int64 `flag:"port"`
+		}{}, "flag: field Other: flag -port is already defined"},
+		{"duplicate nested name", &struct {
+			A     Inner `flag:"a"`
+			APort int   `flag:"a-port"`
+		}{}, "flag: field APort: flag -a-port is already defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := flag.Bind(goflag.NewFlagSet("test", goflag.ContinueOnError), tt.v)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindIPNets(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"include", []string{"-net", "10.0.0.0/8", "-net", "192.168.0.0/16"}, []string{"10.0.0.0/8", "192.168.0.0/16"}},
+		{"merge", []string{"-net", "10.0.0.0/9", "-net", "10.128.0.0/9"}, []string{"10.0.0.0/8"}},
+		{"exclude", []string{"-net", "10.0.0.0/8", "-net", "!10.128.0.0/9"}, []string{"10.0.0.0/9"}},
+		{"exclude csv", []string{"-nets", "10.0.0.0/8,!10.128.0.0/9"}, []string{"10.0.0.0/9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config struct {
+				Net  []*net.IPNet `flag:"net"`
+				Nets []*net.IPNet `flag:"nets" sep:","`
+			}
+			b, err := flag.Bind(goflag.NewFlagSet("test", goflag.ContinueOnError), &config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, n := range append(config.Net, config.Nets...) {
+				got = append(got, n.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
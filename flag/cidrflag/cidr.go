@@ -1,11 +1,19 @@
 package cidrflag
 
 import (
+	"fmt"
 	"net"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // CIDR is a `flag.Value` for CIDR notation IP address and prefix length arguments.
+// The `Family` field restricts the accepted prefixes to IPv4 or IPv6 when set.
 type CIDR struct {
+	driver.Meta
+
+	Family Family
+
 	Value struct {
 		IPNet *net.IPNet
 		IP    net.IP
@@ -15,6 +23,9 @@ type CIDR struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *CIDR) Help() string {
+	if fv.Family != AnyFamily {
+		return fmt.Sprintf("a CIDR notation %s address and prefix length", fv.Family)
+	}
 	return "a CIDR notation IP address and prefix length"
 }
 
@@ -24,14 +35,41 @@ func (fv *CIDR) Set(v string) error {
 	if err != nil {
 		return err
 	}
+	if err := fv.Family.check(ipNet, v); err != nil {
+		return err
+	}
 	fv.Text = v
 	fv.Value = struct {
 		IPNet *net.IPNet
 		IP    net.IP
 	}{IP: ip, IPNet: ipNet}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *CIDR) String() string {
 	return fv.Text
 }
+
+// ValueString returns the parsed network in CIDR notation.
+func (fv *CIDR) ValueString() string {
+	if fv.Value.IPNet == nil {
+		return ""
+	}
+	return fv.Value.IPNet.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *CIDR) ValueType() string {
+	return "cidr"
+}
+
+// Type is pflag.Value.Type
+func (fv *CIDR) Type() string {
+	return fv.ValueType()
+}
+
+// Contains returns whether the network contains ip.
+func (fv *CIDR) Contains(ip net.IP) bool {
+	return fv.Value.IPNet != nil && fv.Value.IPNet.Contains(ip)
+}
@@ -4,12 +4,21 @@ import (
 	"fmt"
 	"net"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // CIDRsCSV is a `flag.Value` for CIDR notation IP address and prefix length arguments.
 // If `Accumulate` is set, the values of all instances of the flag are accumulated.
 // The `Separator` field is used instead of the comma when set.
+// Single IP addresses are accepted as full-length prefixes, and list elements prefixed with `!`
+// exclude their addresses from the ones given before them, as in `10.0.0.0/8,!10.1.0.0/16`.
+// The merged result is kept in the `Prefixes` field, which can be queried using `Contains`.
+// The `Family` field restricts the accepted prefixes to IPv4 or IPv6 when set.
 type CIDRsCSV struct {
+	driver.Meta
+
+	Family     Family
 	Separator  string
 	Accumulate bool
 
@@ -17,7 +26,8 @@ type CIDRsCSV struct {
 		IPNet *net.IPNet
 		IP    net.IP
 	}
-	Texts []string
+	Prefixes PrefixSet
+	Texts    []string
 }
 
 // Help returns a string suitable for inclusion in a flag help message.
@@ -26,7 +36,11 @@ func (fv *CIDRsCSV) Help() string {
 	if fv.Separator != "" {
 		separator = fv.Separator
 	}
-	return fmt.Sprintf("%q-separated list of CIDR notation IP addresses/prefix lengths", separator)
+	family := "IP"
+	if fv.Family != AnyFamily {
+		family = fv.Family.String()
+	}
+	return fmt.Sprintf("%q-separated list of CIDR notation %s addresses/prefix lengths, !prefix to exclude", separator, family)
 }
 
 // Set is flag.Value.Set
@@ -38,23 +52,50 @@ func (fv *CIDRsCSV) Set(v string) error {
 	if !fv.Accumulate {
 		fv.Values = fv.Values[:0]
 		fv.Texts = fv.Texts[:0]
+		fv.Prefixes = PrefixSet{}
 	}
 	parts := strings.Split(v, separator)
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
-		ip, ipNet, err := net.ParseCIDR(part)
+		ip, ipNet, exclude, err := parsePrefix(part, fv.Family)
 		if err != nil {
 			return err
 		}
 		fv.Texts = append(fv.Texts, part)
+		if exclude {
+			fv.Prefixes.Remove(ipNet)
+			continue
+		}
+		fv.Prefixes.Add(ipNet)
 		fv.Values = append(fv.Values, struct {
 			IPNet *net.IPNet
 			IP    net.IP
 		}{IP: ip, IPNet: ipNet})
 	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *CIDRsCSV) String() string {
 	return strings.Join(fv.Texts, ",")
-}
\ No newline at end of file
+}
+
+// ValueString returns the merged networks in CIDR notation.
+func (fv *CIDRsCSV) ValueString() string {
+	return fv.Prefixes.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *CIDRsCSV) ValueType() string {
+	return "cidrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *CIDRsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// Contains returns whether ip is within the merged networks.
+func (fv *CIDRsCSV) Contains(ip net.IP) bool {
+	return fv.Prefixes.Contains(ip)
+}
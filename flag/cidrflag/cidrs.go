@@ -1,38 +1,79 @@
 package cidrflag
 
 import (
+	"fmt"
 	"net"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // CIDRs is a `flag.Value` for CIDR notation IP address and prefix length arguments.
+// Single IP addresses are accepted as full-length prefixes, and arguments prefixed with `!`
+// exclude their addresses from the ones given before them.
+// The merged result is kept in the `Prefixes` field, which can be queried using `Contains`.
+// The `Family` field restricts the accepted prefixes to IPv4 or IPv6 when set.
 type CIDRs struct {
+	driver.Meta
+
+	Family Family
+
 	Values []struct {
 		IPNet *net.IPNet
 		IP    net.IP
 	}
-	Texts []string
+	Prefixes PrefixSet
+	Texts    []string
 }
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *CIDRs) Help() string {
-	return "a CIDR notation IP address and prefix length"
+	if fv.Family != AnyFamily {
+		return fmt.Sprintf("a CIDR notation %s address and prefix length, or !prefix to exclude", fv.Family)
+	}
+	return "a CIDR notation IP address and prefix length, or !prefix to exclude"
 }
 
 // Set is flag.Value.Set
 func (fv *CIDRs) Set(v string) error {
-	ip, ipNet, err := net.ParseCIDR(v)
+	ip, ipNet, exclude, err := parsePrefix(v, fv.Family)
 	if err != nil {
 		return err
 	}
 	fv.Texts = append(fv.Texts, v)
-	fv.Values = append(fv.Values, struct {
-		IPNet *net.IPNet
-		IP    net.IP
-	}{IP: ip, IPNet: ipNet})
+	if exclude {
+		fv.Prefixes.Remove(ipNet)
+	} else {
+		fv.Prefixes.Add(ipNet)
+		fv.Values = append(fv.Values, struct {
+			IPNet *net.IPNet
+			IP    net.IP
+		}{IP: ip, IPNet: ipNet})
+	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *CIDRs) String() string {
 	return strings.Join(fv.Texts, ",")
 }
+
+// ValueString returns the merged networks in CIDR notation.
+func (fv *CIDRs) ValueString() string {
+	return fv.Prefixes.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *CIDRs) ValueType() string {
+	return "cidrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *CIDRs) Type() string {
+	return fv.ValueType()
+}
+
+// Contains returns whether ip is within the merged networks.
+func (fv *CIDRs) Contains(ip net.IP) bool {
+	return fv.Prefixes.Contains(ip)
+}
@@ -0,0 +1,275 @@
+package cidrflag
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Family restricts the address family of the prefixes accepted by a value.
+type Family int
+
+const (
+	// AnyFamily accepts both IPv4 and IPv6 prefixes.
+	AnyFamily Family = iota
+	// IPv4 accepts IPv4 prefixes only.
+	IPv4
+	// IPv6 accepts IPv6 prefixes only.
+	IPv6
+)
+
+func (f Family) String() string {
+	switch f {
+	case IPv4:
+		return "IPv4"
+	case IPv6:
+		return "IPv6"
+	}
+	return "IP"
+}
+
+// check returns an error if ipNet does not belong to the family.
+func (f Family) check(ipNet *net.IPNet, v string) error {
+	isV4 := len(ipNet.Mask) == net.IPv4len
+	if (f == IPv4 && !isV4) || (f == IPv6 && isV4) {
+		return fmt.Errorf(`"%s" must be an %s prefix`, v, f)
+	}
+	return nil
+}
+
+// PrefixSet is a set of IP addresses built from included and excluded prefixes.
+// Overlapping and adjacent prefixes are merged, so lookups and the aggregated
+// prefix list do not depend on the order or redundancy of the input.
+// IPv4 and IPv6 addresses are kept apart, as with net.IPNet.Contains.
+// The zero value is an empty set.
+type PrefixSet struct {
+	v4 []interval
+	v6 []interval
+}
+
+// Add adds all addresses of ipNet to the set.
+func (s *PrefixSet) Add(ipNet *net.IPNet) {
+	r, v4 := toInterval(ipNet)
+	list := s.list(v4)
+	i := sort.Search(len(*list), func(i int) bool {
+		return (*list)[i].touches(r)
+	})
+	j := i
+	for j < len(*list) && r.touches((*list)[j]) {
+		if (*list)[j].from.less(r.from) {
+			r.from = (*list)[j].from
+		}
+		if r.to.less((*list)[j].to) {
+			r.to = (*list)[j].to
+		}
+		j++
+	}
+	merged := append([]interval{}, (*list)[:i]...)
+	merged = append(merged, r)
+	*list = append(merged, (*list)[j:]...)
+}
+
+// Remove removes all addresses of ipNet from the set.
+func (s *PrefixSet) Remove(ipNet *net.IPNet) {
+	r, v4 := toInterval(ipNet)
+	list := s.list(v4)
+	var kept []interval
+	for _, x := range *list {
+		if x.to.less(r.from) || r.to.less(x.from) {
+			kept = append(kept, x)
+			continue
+		}
+		if x.from.less(r.from) {
+			kept = append(kept, interval{from: x.from, to: r.from.sub1()})
+		}
+		if r.to.less(x.to) {
+			kept = append(kept, interval{from: r.to.add1(), to: x.to})
+		}
+	}
+	*list = kept
+}
+
+// Contains returns whether ip is in the set.
+func (s *PrefixSet) Contains(ip net.IP) bool {
+	var x uint128
+	var list []interval
+	if ip4 := ip.To4(); ip4 != nil {
+		x, list = uint128{lo: uint64(binary.BigEndian.Uint32(ip4))}, s.v4
+	} else if ip16 := ip.To16(); ip16 != nil {
+		x, list = uint128{hi: binary.BigEndian.Uint64(ip16[:8]), lo: binary.BigEndian.Uint64(ip16[8:])}, s.v6
+	} else {
+		return false
+	}
+	i := sort.Search(len(list), func(i int) bool {
+		return !list[i].to.less(x)
+	})
+	return i < len(list) && !x.less(list[i].from)
+}
+
+// Len returns the number of disjoint address ranges in the set.
+func (s *PrefixSet) Len() int {
+	return len(s.v4) + len(s.v6)
+}
+
+// Prefixes returns the smallest list of prefixes covering exactly the addresses in the set,
+// IPv4 prefixes first.
+func (s *PrefixSet) Prefixes() []*net.IPNet {
+	var prefixes []*net.IPNet
+	for _, r := range s.v4 {
+		prefixes = r.prefixes(prefixes, 32)
+	}
+	for _, r := range s.v6 {
+		prefixes = r.prefixes(prefixes, 128)
+	}
+	return prefixes
+}
+
+func (s *PrefixSet) String() string {
+	prefixes := s.Prefixes()
+	texts := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		texts[i] = p.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+func (s *PrefixSet) list(v4 bool) *[]interval {
+	if v4 {
+		return &s.v4
+	}
+	return &s.v6
+}
+
+// interval is an inclusive range of addresses. IPv4 addresses use the low 32 bits only.
+type interval struct {
+	from, to uint128
+}
+
+// touches returns whether r starts at most one address after the end of x.
+func (x interval) touches(r interval) bool {
+	return !x.to.less(r.from) || x.to.add1() == r.from
+}
+
+func toInterval(ipNet *net.IPNet) (interval, bool) {
+	ones, size := ipNet.Mask.Size()
+	var from uint128
+	if ip4 := ipNet.IP.To4(); size == 32 && ip4 != nil {
+		from = uint128{lo: uint64(binary.BigEndian.Uint32(ip4))}
+	} else {
+		ip16 := ipNet.IP.To16()
+		from = uint128{hi: binary.BigEndian.Uint64(ip16[:8]), lo: binary.BigEndian.Uint64(ip16[8:])}
+	}
+	host := hostMask(uint(size - ones))
+	from = from.and(host.not())
+	return interval{from: from, to: from.or(host)}, size == 32
+}
+
+// prefixes appends the prefixes covering the interval to dst.
+func (r interval) prefixes(dst []*net.IPNet, size int) []*net.IPNet {
+	from := r.from
+	for {
+		k := from.trailingZeros()
+		if k > size {
+			k = size
+		}
+		for k > 0 && r.to.less(from.or(hostMask(uint(k)))) {
+			k--
+		}
+		dst = append(dst, from.ipNet(size, size-k))
+		last := from.or(hostMask(uint(k)))
+		if last == r.to {
+			return dst
+		}
+		from = last.add1()
+	}
+}
+
+type uint128 struct {
+	hi, lo uint64
+}
+
+func hostMask(n uint) uint128 {
+	switch {
+	case n == 0:
+		return uint128{}
+	case n < 64:
+		return uint128{lo: 1<<n - 1}
+	case n < 128:
+		return uint128{hi: 1<<(n-64) - 1, lo: ^uint64(0)}
+	}
+	return uint128{hi: ^uint64(0), lo: ^uint64(0)}
+}
+
+func (u uint128) less(v uint128) bool {
+	return u.hi < v.hi || (u.hi == v.hi && u.lo < v.lo)
+}
+
+func (u uint128) and(v uint128) uint128 {
+	return uint128{hi: u.hi & v.hi, lo: u.lo & v.lo}
+}
+
+func (u uint128) or(v uint128) uint128 {
+	return uint128{hi: u.hi | v.hi, lo: u.lo | v.lo}
+}
+
+func (u uint128) not() uint128 {
+	return uint128{hi: ^u.hi, lo: ^u.lo}
+}
+
+func (u uint128) add1() uint128 {
+	lo, carry := bits.Add64(u.lo, 1, 0)
+	return uint128{hi: u.hi + carry, lo: lo}
+}
+
+func (u uint128) sub1() uint128 {
+	lo, borrow := bits.Sub64(u.lo, 1, 0)
+	return uint128{hi: u.hi - borrow, lo: lo}
+}
+
+func (u uint128) trailingZeros() int {
+	if u.lo != 0 {
+		return bits.TrailingZeros64(u.lo)
+	}
+	return 64 + bits.TrailingZeros64(u.hi)
+}
+
+func (u uint128) ipNet(size, ones int) *net.IPNet {
+	if size == 32 {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, uint32(u.lo))
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 32)}
+	}
+	ip := make(net.IP, net.IPv6len)
+	binary.BigEndian.PutUint64(ip[:8], u.hi)
+	binary.BigEndian.PutUint64(ip[8:], u.lo)
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 128)}
+}
+
+// parsePrefix parses a CIDR notation prefix or a single IP address, optionally prefixed with `!`.
+func parsePrefix(v string, family Family) (ip net.IP, ipNet *net.IPNet, exclude bool, err error) {
+	text := strings.TrimSpace(v)
+	if strings.HasPrefix(text, "!") {
+		exclude = true
+		text = strings.TrimSpace(text[1:])
+	}
+	if !strings.Contains(text, "/") {
+		ip = net.ParseIP(text)
+		if ip == nil {
+			return nil, nil, false, fmt.Errorf(`not a valid IP address or prefix: "%s"`, v)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ipNet = &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+		} else {
+			ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+		}
+	} else if ip, ipNet, err = net.ParseCIDR(text); err != nil {
+		return nil, nil, false, err
+	}
+	if err := family.check(ipNet, v); err != nil {
+		return nil, nil, false, err
+	}
+	return ip, ipNet, exclude, nil
+}
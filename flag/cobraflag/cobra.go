@@ -0,0 +1,188 @@
+package cobraflag
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/gofunct/functional/flag/constflag"
+	"github.com/gofunct/functional/flag/fileflag"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// CompletionFunc is a cobra flag completion function.
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// Var defines a flag with the specified name, shorthand and usage string on the local flags of cmd,
+// and registers a shell-completion function for it if the value offers one.
+func Var(cmd *cobra.Command, value pflag.Value, name, shorthand, usage string) error {
+	return define(cmd, cmd.Flags(), value, name, shorthand, usage)
+}
+
+// PersistentVar defines a flag with the specified name, shorthand and usage string on the persistent flags of cmd,
+// and registers a shell-completion function for it if the value offers one.
+func PersistentVar(cmd *cobra.Command, value pflag.Value, name, shorthand, usage string) error {
+	return define(cmd, cmd.PersistentFlags(), value, name, shorthand, usage)
+}
+
+// AddFlagSet adds all flags of a standard library flag set (e.g. one populated by `flag.Bind`)
+// to the local flags of cmd, registering shell-completion functions where available.
+func AddFlagSet(cmd *cobra.Command, fs *flag.FlagSet) error {
+	cmd.Flags().AddGoFlagSet(fs)
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if complete := Completion(f.Value); complete != nil && err == nil {
+			err = cmd.RegisterFlagCompletionFunc(f.Name, complete)
+		}
+	})
+	return err
+}
+
+func define(cmd *cobra.Command, fs *pflag.FlagSet, value pflag.Value, name, shorthand, usage string) error {
+	if named, ok := value.(interface {
+		SetName(string)
+	}); ok {
+		named.SetName(name)
+	}
+	fs.VarP(value, name, shorthand, usage)
+	if complete := Completion(value); complete != nil {
+		return cmd.RegisterFlagCompletionFunc(name, complete)
+	}
+	return nil
+}
+
+// Completion returns a shell-completion function for the value, or nil if it offers none.
+// Enum values complete their `Choices`, glob values complete filesystem matches
+// and file and directory values fall back to the shell's file and directory completion.
+// Separated lists complete the part after the last separator.
+func Completion(value flag.Value) CompletionFunc {
+	switch fv := value.(type) {
+	case *constflag.Enum:
+		return completeChoices(fv.Choices, fv.CaseSensitive)
+	case *constflag.Enums:
+		return completeChoices(fv.Choices, fv.CaseSensitive)
+	case *constflag.EnumsCSV:
+		return completeList(separator(fv.Separator), completeChoices(fv.Choices, fv.CaseSensitive))
+	case *constflag.EnumSet:
+		return completeChoices(fv.Choices, fv.CaseSensitive)
+	case *constflag.EnumSetCSV:
+		return completeList(separator(fv.Separator), completeChoices(fv.Choices, fv.CaseSensitive))
+	case *constflag.Either:
+		return completeEither(Completion(fv.Either), Completion(fv.Or))
+	case *fileflag.Glob, *fileflag.Globs:
+		return completeGlob
+	case *fileflag.File, *fileflag.Files, *fileflag.FileContents, *fileflag.FileJSON, *fileflag.FileYAML:
+		return completeFile
+	case *fileflag.Dir:
+		return completeDir
+	case *typeflag.Wrap:
+		return Completion(fv.Value)
+	case *typeflag.WrapCSV:
+		return completeList(separator(fv.Separator), Completion(fv.Value))
+	}
+	return nil
+}
+
+func separator(sep string) string {
+	if sep == "" {
+		return ","
+	}
+	return sep
+}
+
+// completeChoices completes the choices matching the typed prefix.
+func completeChoices(choices []string, caseSensitive bool) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		hasPrefix := func(s, prefix string) bool {
+			return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+		}
+		if caseSensitive {
+			hasPrefix = strings.HasPrefix
+		}
+		var out []string
+		for _, c := range choices {
+			if hasPrefix(c, toComplete) {
+				out = append(out, c)
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeList completes only the part after the last separator with complete,
+// keeping the typed values before it. No space is added so more values can follow.
+func completeList(sep string, complete CompletionFunc) CompletionFunc {
+	if complete == nil {
+		return nil
+	}
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var done string
+		if i := strings.LastIndex(toComplete, sep); i >= 0 {
+			done, toComplete = toComplete[:i+len(sep)], toComplete[i+len(sep):]
+		}
+		out, directive := complete(cmd, args, toComplete)
+		for i := range out {
+			out[i] = done + out[i]
+		}
+		return out, directive | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completeEither offers the completions of both alternatives.
+func completeEither(either, or CompletionFunc) CompletionFunc {
+	if either == nil {
+		return or
+	}
+	if or == nil {
+		return either
+	}
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		a, directiveA := either(cmd, args, toComplete)
+		b, directiveB := or(cmd, args, toComplete)
+		return append(a, b...), mergeDirectives(directiveA, directiveB)
+	}
+}
+
+// mergeDirectives combines the directives of two completions.
+// A failed completion defers to the other one, file completion is suppressed only
+// if both suppress it and the remaining flags of either apply.
+func mergeDirectives(a, b cobra.ShellCompDirective) cobra.ShellCompDirective {
+	if a&cobra.ShellCompDirectiveError != 0 {
+		return b
+	}
+	if b&cobra.ShellCompDirectiveError != 0 {
+		return a
+	}
+	directive := (a | b) &^ cobra.ShellCompDirectiveNoFileComp
+	if a&b&cobra.ShellCompDirectiveNoFileComp != 0 {
+		directive |= cobra.ShellCompDirectiveNoFileComp
+	}
+	return directive
+}
+
+// completeGlob completes the filesystem entries starting with the typed prefix.
+// Directories are suffixed with a separator so completion can descend into them.
+func completeGlob(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	matches, err := filepath.Glob(toComplete + "*")
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	for i, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			matches[i] = match + string(filepath.Separator)
+		}
+	}
+	return matches, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeFile(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveDefault
+}
+
+func completeDir(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
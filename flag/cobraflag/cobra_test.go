@@ -0,0 +1,193 @@
+package cobraflag_test
+
+import (
+	goflag "flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gofunct/functional/flag/cobraflag"
+	"github.com/gofunct/functional/flag/constflag"
+	"github.com/gofunct/functional/flag/fileflag"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestCompletion(t *testing.T) {
+	choices := []string{"Json", "yaml", "yml", "text"}
+	tests := []struct {
+		name          string
+		value         goflag.Value
+		toComplete    string
+		want          []string
+		wantDirective cobra.ShellCompDirective
+	}{
+		{
+			name:          "enum prefix",
+			value:         &constflag.Enum{Choices: choices},
+			toComplete:    "y",
+			want:          []string{"yaml", "yml"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "enum case-insensitive",
+			value:         &constflag.Enum{Choices: choices},
+			toComplete:    "JS",
+			want:          []string{"Json"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "enum case-sensitive",
+			value:         &constflag.Enum{Choices: choices, CaseSensitive: true},
+			toComplete:    "js",
+			want:          nil,
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "enum no match",
+			value:         &constflag.Enum{Choices: choices},
+			toComplete:    "x",
+			want:          nil,
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "enum set",
+			value:         &constflag.EnumSet{Choices: choices},
+			toComplete:    "t",
+			want:          []string{"text"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "enums csv first value",
+			value:         &constflag.EnumsCSV{Choices: choices},
+			toComplete:    "y",
+			want:          []string{"yaml", "yml"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		{
+			name:          "enums csv after separator",
+			value:         &constflag.EnumsCSV{Choices: choices},
+			toComplete:    "text,Y",
+			want:          []string{"text,yaml", "text,yml"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		{
+			name:          "enum set csv custom separator",
+			value:         &constflag.EnumSetCSV{Choices: choices, Separator: ";"},
+			toComplete:    "json;yaml;t",
+			want:          []string{"json;yaml;text"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		{
+			name:          "file",
+			value:         &fileflag.File{},
+			toComplete:    "",
+			want:          nil,
+			wantDirective: cobra.ShellCompDirectiveDefault,
+		},
+		{
+			name:          "dir",
+			value:         &fileflag.Dir{},
+			toComplete:    "",
+			want:          nil,
+			wantDirective: cobra.ShellCompDirectiveFilterDirs,
+		},
+		{
+			name:          "wrap",
+			value:         &typeflag.Wrap{Value: &constflag.Enum{Choices: choices}},
+			toComplete:    "te",
+			want:          []string{"text"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "wrap csv after separator",
+			value:         &typeflag.WrapCSV{Value: &constflag.Enum{Choices: choices}},
+			toComplete:    "json,te",
+			want:          []string{"json,text"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace,
+		},
+		{
+			name:          "either merges choices",
+			value:         &constflag.Either{Either: &constflag.Enum{Choices: []string{"auto"}}, Or: &constflag.Enum{Choices: []string{"always", "never"}}},
+			toComplete:    "a",
+			want:          []string{"auto", "always"},
+			wantDirective: cobra.ShellCompDirectiveNoFileComp,
+		},
+		{
+			name:          "either keeps file completion",
+			value:         &constflag.Either{Either: &constflag.Enum{Choices: []string{"stdout"}}, Or: &fileflag.File{}},
+			toComplete:    "s",
+			want:          []string{"stdout"},
+			wantDirective: cobra.ShellCompDirectiveDefault,
+		},
+		{
+			name:          "either keeps no space",
+			value:         &constflag.Either{Either: &constflag.EnumsCSV{Choices: choices}, Or: &fileflag.Dir{}},
+			toComplete:    "te",
+			want:          []string{"text"},
+			wantDirective: cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveFilterDirs,
+		},
+		{
+			name:          "either with one completion",
+			value:         &constflag.Either{Either: &typeflag.Wrap{}, Or: &fileflag.Dir{}},
+			toComplete:    "",
+			want:          nil,
+			wantDirective: cobra.ShellCompDirectiveFilterDirs,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complete := cobraflag.Completion(tt.value)
+			if complete == nil {
+				t.Fatal("Completion() = nil")
+			}
+			got, directive := complete(nil, nil, tt.toComplete)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+			if directive != tt.wantDirective {
+				t.Errorf("directive = %v, want %v", directive, tt.wantDirective)
+			}
+		})
+	}
+}
+
+func TestCompletionNone(t *testing.T) {
+	for _, value := range []goflag.Value{
+		&typeflag.Wrap{},
+		&typeflag.WrapCSV{},
+		&constflag.Either{},
+	} {
+		if complete := cobraflag.Completion(value); complete != nil {
+			t.Errorf("Completion(%T) != nil", value)
+		}
+	}
+}
+
+func TestCompletionGlob(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sample.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "other.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	complete := cobraflag.Completion(&fileflag.Glob{})
+	if complete == nil {
+		t.Fatal("Completion() = nil")
+	}
+	got, directive := complete(nil, nil, filepath.Join(dir, "s"))
+	want := []string{filepath.Join(dir, "sample.txt"), filepath.Join(dir, "sub") + string(filepath.Separator)}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("completions = %q, want %q", got, want)
+	}
+	if want := cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp; directive != want {
+		t.Errorf("directive = %v, want %v", directive, want)
+	}
+}
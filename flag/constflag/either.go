@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/gofunct/functional/errors"
+	"github.com/gofunct/functional/flag/driver"
 	"os"
 	"strings"
 )
@@ -11,10 +12,12 @@ import (
 // Either tries to parse the argument using `Either`, and if that fails, using `Or`.
 // `ChoseEither` is true if the first attempt succeed.
 type Either struct {
-	Either   flag.Value
-	Or       flag.Value
+	driver.Meta
+
+	Either      flag.Value
+	Or          flag.Value
 	ChoseEither bool
-	Env 	 string
+	Env         string
 }
 
 // Help returns a string suitable for inclusion in a flag help message.
@@ -34,15 +37,21 @@ func (fv *Either) Help() string {
 }
 
 // Set is flag.Value.Set
+// If both values fail to parse the argument, the returned error contains both errors.
 func (fv *Either) Set(v string) error {
-	if err := os.Setenv(fv.Env, v); err != nil {
-		return errors.Wrap(err, "failed to bind env to flag value")
+	eitherErr := fv.Either.Set(v)
+	fv.ChoseEither = eitherErr == nil
+	if eitherErr != nil {
+		if orErr := fv.Or.Set(v); orErr != nil {
+			return fmt.Errorf(`"%s" matched neither value: %v; %v`, v, eitherErr, orErr)
+		}
 	}
-	err := fv.Either.Set(v)
-	fv.ChoseEither = err == nil
-	if err != nil {
-		return fv.Or.Set(v)
+	if fv.Env != "" {
+		if err := os.Setenv(strings.ToUpper(fv.Env), v); err != nil {
+			return errors.Wrap(err, "failed to bind env to flag value")
+		}
 	}
+	fv.MarkChanged()
 	return nil
 }
 
@@ -59,4 +68,28 @@ func (fv *Either) String() string {
 		return fv.Or.String()
 	}
 	return ""
-}
\ No newline at end of file
+}
+
+// ValueString returns the `ValueString` of the chosen value.
+func (fv *Either) ValueString() string {
+	if fv.ChoseEither {
+		return driver.ValueString(fv.Either)
+	}
+	if fv.Or != nil {
+		return driver.ValueString(fv.Or)
+	}
+	return ""
+}
+
+// ValueType returns the type name of the value.
+func (fv *Either) ValueType() string {
+	if fv.Either != nil && fv.Or != nil {
+		return driver.ValueType(fv.Either) + "|" + driver.ValueType(fv.Or)
+	}
+	return "either"
+}
+
+// Type is pflag.Value.Type
+func (fv *Either) Type() string {
+	return fv.ValueType()
+}
@@ -3,12 +3,17 @@ package constflag
 import (
 	"fmt"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+	"github.com/gofunct/functional/flag/typeflag"
 )
 
 // Enum is a `flag.Value` for one-of-a-fixed-set string arguments.
 // The value of the `Choices` field defines the valid choices.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type Enum struct {
+	driver.Meta
+
 	Choices       []string
 	CaseSensitive bool
 	Default       string
@@ -16,47 +21,39 @@ type Enum struct {
 	Text          string
 }
 
-func (fv *Enum) HasChanged() bool {
-	if fv.Default != fv.Value {
-		return true
+// ValueString returns the matched choice, or `Default` if none was set.
+func (fv *Enum) ValueString() string {
+	if fv.Value == "" {
+		return fv.Default
 	}
-	return false
-}
-
-func (fv *Enum) Name() string {
-	panic("implement me")
+	return fv.Value
 }
 
-func (fv *Enum) ValueString() string {
-	panic("implement me")
+// ValueType returns the type name of the value.
+func (fv *Enum) ValueType() string {
+	return "enum"
 }
 
-func (fv *Enum) ValueType() string {
-	panic("implement me")
+// Type is pflag.Value.Type
+func (fv *Enum) Type() string {
+	return fv.ValueType()
 }
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *Enum) Help() string {
-	if fv.CaseSensitive {
-		return fmt.Sprintf("one of %v (case-sensitive)", fv.Choices)
-	}
-	return fmt.Sprintf("one of %v", fv.Choices)
+	return enumParser{fv.Choices, fv.CaseSensitive}.Describe(false)
 }
 
 // Set is flag.Value.Set
 func (fv *Enum) Set(v string) error {
-	fv.Text = v
-	equal := strings.EqualFold
-	if fv.CaseSensitive {
-		equal = func(a, b string) bool { return a == b }
+	value, err := enumParser{fv.Choices, fv.CaseSensitive}.Parse(v)
+	if err != nil {
+		return err
 	}
-	for _, c := range fv.Choices {
-		if equal(c, v) {
-			fv.Value = c
-			return nil
-		}
-	}
-	return fmt.Errorf(`"%s" must be one of [%s]`, v, strings.Join(fv.Choices, " "))
+	fv.Text = v
+	fv.Value = value
+	fv.MarkChanged()
+	return nil
 }
 
 func (fv *Enum) String() string {
@@ -67,6 +64,8 @@ func (fv *Enum) String() string {
 // The value of the `Choices` field defines the valid choices.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type Enums struct {
+	driver.Meta
+
 	Choices       []string
 	CaseSensitive bool
 
@@ -76,38 +75,47 @@ type Enums struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *Enums) Help() string {
-	if fv.CaseSensitive {
-		return fmt.Sprintf("one of %v (case-sensitive)", fv.Choices)
-	}
-	return fmt.Sprintf("one of %v", fv.Choices)
+	return enumParser{fv.Choices, fv.CaseSensitive}.Describe(false)
 }
 
 // Set is flag.Value.Set
 func (fv *Enums) Set(v string) error {
-	equal := strings.EqualFold
-	if fv.CaseSensitive {
-		equal = func(a, b string) bool { return a == b }
-	}
-	for _, c := range fv.Choices {
-		if equal(c, v) {
-			fv.Values = append(fv.Values, c)
-			fv.Texts = append(fv.Texts, v)
-			return nil
-		}
+	values, texts, err := typeflag.Append[string](enumParser{fv.Choices, fv.CaseSensitive}, fv.Values, fv.Texts, v, false, nil)
+	if err != nil {
+		return err
 	}
-	return fmt.Errorf(`"%s" must be one of [%s]`, v, strings.Join(fv.Choices, " "))
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
+	return nil
 }
 
 func (fv *Enums) String() string {
 	return strings.Join(fv.Values, ",")
 }
 
+// ValueString returns the matched choices.
+func (fv *Enums) ValueString() string {
+	return strings.Join(fv.Values, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Enums) ValueType() string {
+	return "enumSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Enums) Type() string {
+	return fv.ValueType()
+}
+
 // EnumsCSV is a `flag.Value` for comma-separated enum arguments.
 // The value of the `Choices` field defines the valid choices.
 // If `Accumulate` is set, the values of all instances of the flag are accumulated.
 // The `Separator` field is used instead of the comma when set.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type EnumsCSV struct {
+	driver.Meta
+
 	Choices       []string
 	Separator     string
 	Accumulate    bool
@@ -119,50 +127,73 @@ type EnumsCSV struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *EnumsCSV) Help() string {
-	separator := ","
-	if fv.Separator != "" {
-		separator = fv.Separator
-	}
-	if fv.CaseSensitive {
-		return fmt.Sprintf("%q-separated list of values from %v (case-sensitive)", separator, fv.Choices)
-	}
-	return fmt.Sprintf("%q-separated list of values from %v", separator, fv.Choices)
+	return typeflag.CSVHelp[string](enumParser{fv.Choices, fv.CaseSensitive}, fv.Separator)
 }
 
 // Set is flag.Value.Set
 func (fv *EnumsCSV) Set(v string) error {
-	equal := strings.EqualFold
-	if fv.CaseSensitive {
-		equal = func(a, b string) bool { return a == b }
-	}
-	separator := fv.Separator
-	if separator == "" {
-		separator = ","
-	}
+	values, texts := fv.Values, fv.Texts
 	if !fv.Accumulate {
-		fv.Values = fv.Values[:0]
+		values, texts = nil, nil
 	}
-	parts := strings.Split(v, separator)
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		var ok bool
-		var value string
-		for _, c := range fv.Choices {
-			if equal(c, part) {
-				value = c
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			return fmt.Errorf(`"%s" must be one of [%s]`, v, strings.Join(fv.Choices, " "))
-		}
-		fv.Values = append(fv.Values, value)
-		fv.Texts = append(fv.Texts, part)
+	values, texts, err := typeflag.AppendCSV[string](enumParser{fv.Choices, fv.CaseSensitive}, values, texts, v, fv.Separator, false, nil)
+	if err != nil {
+		return err
 	}
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *EnumsCSV) String() string {
 	return strings.Join(fv.Values, ",")
 }
+
+// ValueString returns the matched choices.
+func (fv *EnumsCSV) ValueString() string {
+	return strings.Join(fv.Values, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *EnumsCSV) ValueType() string {
+	return "enumSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *EnumsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// enumParser is the typeflag.Parser matching arguments against the choices of an enum.
+type enumParser struct {
+	choices       []string
+	caseSensitive bool
+}
+
+func (p enumParser) Parse(v string) (string, error) {
+	equal := strings.EqualFold
+	if p.caseSensitive {
+		equal = func(a, b string) bool { return a == b }
+	}
+	for _, c := range p.choices {
+		if equal(c, v) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf(`"%s" must be one of [%s]`, v, strings.Join(p.choices, " "))
+}
+
+func (p enumParser) Format(value string) string { return value }
+
+func (p enumParser) Describe(plural bool) string {
+	help := fmt.Sprintf("one of %v", p.choices)
+	if plural {
+		help = fmt.Sprintf("values from %v", p.choices)
+	}
+	if p.caseSensitive {
+		help += " (case-sensitive)"
+	}
+	return help
+}
+
+func (p enumParser) TypeName() string { return "enum" }
@@ -0,0 +1,66 @@
+package constflag_test
+
+import (
+	goflag "flag"
+	"testing"
+
+	"github.com/gofunct/functional/flag/constflag"
+	"github.com/gofunct/functional/flag/driver"
+)
+
+func TestEnumChanged(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantChanged bool
+		wantSource  driver.Source
+		wantValue   string
+	}{
+		{"unset", nil, false, driver.SourceDefault, "slow"},
+		{"other", []string{"-mode", "fast"}, true, driver.SourceFlag, "fast"},
+		{"same as default", []string{"-mode", "slow"}, true, driver.SourceFlag, "slow"},
+		{"same as default different case", []string{"-mode", "SLOW"}, true, driver.SourceFlag, "slow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+			mode := &constflag.Enum{Choices: []string{"fast", "slow"}, Default: "slow"}
+			fs.Var(mode, "mode", mode.Help())
+			if mode.HasChanged() {
+				t.Fatal("HasChanged() = true before Set")
+			}
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if got := mode.HasChanged(); got != tt.wantChanged {
+				t.Errorf("HasChanged() = %v, want %v", got, tt.wantChanged)
+			}
+			if got := driver.SourceOf(mode); got != tt.wantSource {
+				t.Errorf("SourceOf() = %q, want %q", got, tt.wantSource)
+			}
+			if got := mode.ValueString(); got != tt.wantValue {
+				t.Errorf("ValueString() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestEnumSetSource(t *testing.T) {
+	mode := &constflag.Enum{Choices: []string{"fast", "slow"}, Default: "slow"}
+	if err := mode.Set("slow"); err != nil {
+		t.Fatal(err)
+	}
+	mode.SetSource(driver.SourceEnv)
+	if !mode.HasChanged() {
+		t.Error("HasChanged() = false after Set")
+	}
+	if got := driver.SourceOf(mode); got != driver.SourceEnv {
+		t.Errorf("SourceOf() = %q, want %q", got, driver.SourceEnv)
+	}
+	if err := mode.Set("fast"); err != nil {
+		t.Fatal(err)
+	}
+	if got := driver.SourceOf(mode); got != driver.SourceFlag {
+		t.Errorf("SourceOf() after Set = %q, want %q", got, driver.SourceFlag)
+	}
+}
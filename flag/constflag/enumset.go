@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // EnumSet is a `flag.Value` for one-of-a-fixed-set string arguments.
@@ -11,6 +13,8 @@ import (
 // The value of the `Choices` field defines the valid choices.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type EnumSet struct {
+	driver.Meta
+
 	Choices       []string
 	CaseSensitive bool
 
@@ -57,6 +61,7 @@ func (fv *EnumSet) Set(v string) error {
 	}
 	fv.Value[v] = true
 	fv.Texts = append(fv.Texts, v)
+	fv.MarkChanged()
 	return nil
 }
 
@@ -64,6 +69,20 @@ func (fv *EnumSet) String() string {
 	return strings.Join(fv.Values(), ",")
 }
 
+// ValueString returns the distinct matched choices, sorted.
+func (fv *EnumSet) ValueString() string {
+	return strings.Join(fv.Values(), ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *EnumSet) ValueType() string {
+	return "enumSet"
+}
+
+// Type is pflag.Value.Type
+func (fv *EnumSet) Type() string {
+	return fv.ValueType()
+}
 
 // EnumSetCSV is a `flag.Value` for comma-separated enum arguments.
 // Only distinct values are returned.
@@ -72,6 +91,8 @@ func (fv *EnumSet) String() string {
 // The `Separator` field is used instead of the comma when set.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type EnumSetCSV struct {
+	driver.Meta
+
 	Choices       []string
 	Separator     string
 	Accumulate    bool
@@ -133,9 +154,25 @@ func (fv *EnumSetCSV) Set(v string) error {
 		fv.Value[value] = true
 		fv.Texts = append(fv.Texts, part)
 	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *EnumSetCSV) String() string {
 	return strings.Join(fv.Values(), ",")
 }
+
+// ValueString returns the distinct matched choices, sorted.
+func (fv *EnumSetCSV) ValueString() string {
+	return strings.Join(fv.Values(), ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *EnumSetCSV) ValueType() string {
+	return "enumSet"
+}
+
+// Type is pflag.Value.Type
+func (fv *EnumSetCSV) Type() string {
+	return fv.ValueType()
+}
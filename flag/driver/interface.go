@@ -1,5 +1,10 @@
 package driver
 
+import (
+	"flag"
+)
+
+// Flag is a `flag.Value` that can be introspected generically.
 type Flag interface {
 	Set(string) error
 	String() string
@@ -9,3 +14,110 @@ type Flag interface {
 	ValueType() string
 }
 
+// Sensitive is implemented by flag values holding secrets.
+// Usage output and loggers print the `Redacted` text instead of the value.
+type Sensitive interface {
+	Redacted() string
+}
+
+// Source identifies where the value of a flag came from.
+type Source string
+
+const (
+	// SourceDefault is reported for flags that were never set.
+	SourceDefault Source = "default"
+	// SourceFlag is reported for flags set on the command line.
+	SourceFlag Source = "flag"
+	// SourceEnv is reported for flags set from an environment variable.
+	SourceEnv Source = "env"
+	// SourceConfig is reported for flags set from a config file.
+	SourceConfig Source = "config"
+)
+
+// Meta holds the bookkeeping shared by all flag values implementing `Flag`.
+// It is embedded by the value types in the flag subpackages.
+type Meta struct {
+	FlagName string
+
+	changed bool
+	source  Source
+}
+
+// Name returns the name the flag was registered under.
+func (m *Meta) Name() string {
+	return m.FlagName
+}
+
+// SetName records the name the flag was registered under.
+func (m *Meta) SetName(name string) {
+	m.FlagName = name
+}
+
+// HasChanged returns true if the value has been set since it was created.
+func (m *Meta) HasChanged() bool {
+	return m.changed
+}
+
+// MarkChanged records that the value has been set, forgetting its recorded source
+// until `SetSource` is called again.
+func (m *Meta) MarkChanged() {
+	m.changed = true
+	m.source = ""
+}
+
+// Source returns where the value came from.
+// Values that were set without a recorded source are assumed to come from the command line.
+func (m *Meta) Source() Source {
+	if m.source != "" {
+		return m.source
+	}
+	if m.changed {
+		return SourceFlag
+	}
+	return SourceDefault
+}
+
+// SetSource records where the value came from.
+func (m *Meta) SetSource(source Source) {
+	m.source = source
+}
+
+// Var defines a flag with the specified name and usage string on fs,
+// recording the name on the value when it supports it.
+func Var(fs *flag.FlagSet, value flag.Value, name, usage string) {
+	if named, ok := value.(interface {
+		SetName(string)
+	}); ok {
+		named.SetName(name)
+	}
+	fs.Var(value, name, usage)
+}
+
+// SourceOf returns the `Source` of v if it records one, and `SourceDefault` otherwise.
+func SourceOf(v flag.Value) Source {
+	if s, ok := v.(interface {
+		Source() Source
+	}); ok {
+		return s.Source()
+	}
+	return SourceDefault
+}
+
+// ValueString returns the `ValueString` of v if it implements `Flag`, and its `String` otherwise.
+func ValueString(v flag.Value) string {
+	if v == nil {
+		return ""
+	}
+	if f, ok := v.(Flag); ok {
+		return f.ValueString()
+	}
+	return v.String()
+}
+
+// ValueType returns the `ValueType` of v if it implements `Flag`, and "value" otherwise.
+func ValueType(v flag.Value) string {
+	if f, ok := v.(Flag); ok {
+		return f.ValueType()
+	}
+	return "value"
+}
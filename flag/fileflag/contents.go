@@ -0,0 +1,69 @@
+package fileflag
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// FileContents is a `flag.Value` for arguments naming a file whose contents are read at Set time.
+// The argument may be a path, a path prefixed with `@`, or `-` to read standard input.
+// The value of the `Stdin` field is read instead of os.Stdin when specified.
+type FileContents struct {
+	driver.Meta
+
+	Stdin io.Reader
+
+	Value []byte
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *FileContents) Help() string {
+	return "a file path, @path, or - for stdin"
+}
+
+// Set is flag.Value.Set
+func (fv *FileContents) Set(v string) error {
+	b, err := readContents(v, fv.Stdin)
+	if err != nil {
+		return err
+	}
+	fv.Value = b
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *FileContents) String() string {
+	return fv.Text
+}
+
+// ValueString returns the path the contents were read from.
+func (fv *FileContents) ValueString() string {
+	return fv.Text
+}
+
+// ValueType returns the type name of the value.
+func (fv *FileContents) ValueType() string {
+	return "fileContents"
+}
+
+// Type is pflag.Value.Type
+func (fv *FileContents) Type() string {
+	return fv.ValueType()
+}
+
+// readContents reads the file named by v, which may be prefixed with `@`, or stdin if v is `-`.
+func readContents(v string, stdin io.Reader) ([]byte, error) {
+	if v == "-" {
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(strings.TrimPrefix(v, "@"))
+}
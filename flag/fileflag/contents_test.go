@@ -0,0 +1,57 @@
+package fileflag_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofunct/functional/flag/fileflag"
+)
+
+// writeFile writes content to name in a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileContents(t *testing.T) {
+	path := writeFile(t, "data.txt", "from file\n")
+	tests := []struct {
+		name    string
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{name: "path", arg: path, want: "from file\n"},
+		{name: "at path", arg: "@" + path, want: "from file\n"},
+		{name: "stdin", arg: "-", want: "from stdin"},
+		{name: "missing", arg: filepath.Join(t.TempDir(), "missing"), wantErr: true},
+		{name: "missing at path", arg: "@", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := &fileflag.FileContents{Stdin: strings.NewReader("from stdin")}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				if fv.HasChanged() {
+					t.Error("failed Set marked the value changed")
+				}
+				return
+			}
+			if string(fv.Value) != tt.want {
+				t.Errorf("Value = %q, want %q", fv.Value, tt.want)
+			}
+			if fv.String() != tt.arg || fv.ValueString() != tt.arg {
+				t.Errorf("String = %q, ValueString = %q, want %q", fv.String(), fv.ValueString(), tt.arg)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package fileflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/gofunct/functional/encoding"
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// FileJSON is a `flag.Value` for arguments naming a JSON file that is decoded at Set time.
+// The argument may be a path, a path prefixed with `@`, or `-` to read standard input.
+// If non-nil, the `Value` field is used as the decoding target.
+type FileJSON struct {
+	driver.Meta
+
+	Stdin io.Reader
+
+	Value interface{}
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *FileJSON) Help() string {
+	return "a JSON file path, @path, or - for stdin"
+}
+
+// Set is flag.Value.Set
+func (fv *FileJSON) Set(v string) error {
+	if err := decodeFile(v, "json", fv.Stdin, &fv.Value); err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *FileJSON) String() string {
+	return fv.Text
+}
+
+// ValueString returns the decoded value re-encoded as JSON.
+func (fv *FileJSON) ValueString() string {
+	return encodedString(fv.Value, fv.Text)
+}
+
+// ValueType returns the type name of the value.
+func (fv *FileJSON) ValueType() string {
+	return "jsonFile"
+}
+
+// Type is pflag.Value.Type
+func (fv *FileJSON) Type() string {
+	return fv.ValueType()
+}
+
+// FileYAML is a `flag.Value` for arguments naming a YAML file that is decoded at Set time.
+// The argument may be a path, a path prefixed with `@`, or `-` to read standard input.
+// If non-nil, the `Value` field is used as the decoding target.
+type FileYAML struct {
+	driver.Meta
+
+	Stdin io.Reader
+
+	Value interface{}
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *FileYAML) Help() string {
+	return "a YAML file path, @path, or - for stdin"
+}
+
+// Set is flag.Value.Set
+func (fv *FileYAML) Set(v string) error {
+	if err := decodeFile(v, "yaml", fv.Stdin, &fv.Value); err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *FileYAML) String() string {
+	return fv.Text
+}
+
+// ValueString returns the decoded value re-encoded as JSON.
+func (fv *FileYAML) ValueString() string {
+	return encodedString(fv.Value, fv.Text)
+}
+
+// ValueType returns the type name of the value.
+func (fv *FileYAML) ValueType() string {
+	return "yamlFile"
+}
+
+// Type is pflag.Value.Type
+func (fv *FileYAML) Type() string {
+	return fv.ValueType()
+}
+
+// decodeFile reads the file named by v and decodes it with the encoding.DefaultDecoders entry for format.
+// If *target is non-nil it is decoded into, otherwise *target is set to the generic decoded value.
+func decodeFile(v, format string, stdin io.Reader, target *interface{}) error {
+	maker, ok := encoding.DefaultDecoders[format]
+	if !ok {
+		return fmt.Errorf("no decoder for %s", format)
+	}
+	b, err := readContents(v, stdin)
+	if err != nil {
+		return err
+	}
+	decoder := maker.NewDecoder(bytes.NewReader(b))
+	if *target != nil {
+		err = decoder.Decode(*target)
+	} else {
+		err = decoder.Decode(target)
+	}
+	if err != nil {
+		return fmt.Errorf(`"%s" is not a valid %s file: %v`, v, format, err)
+	}
+	return nil
+}
+
+func encodedString(value interface{}, text string) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return text
+	}
+	return string(b)
+}
@@ -0,0 +1,78 @@
+package fileflag_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofunct/functional/flag/fileflag"
+)
+
+func TestFileJSON(t *testing.T) {
+	path := writeFile(t, "config.json", `{"name": "app", "ports": [80, 443]}`)
+	invalid := writeFile(t, "invalid.json", `{"name": `)
+
+	fv := &fileflag.FileJSON{}
+	if err := fv.Set(path); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"name": "app", "ports": []interface{}{80.0, 443.0}}
+	if !reflect.DeepEqual(fv.Value, want) {
+		t.Errorf("Value = %#v, want %#v", fv.Value, want)
+	}
+	if got := fv.ValueString(); got != `{"name":"app","ports":[80,443]}` {
+		t.Errorf("ValueString = %s", got)
+	}
+
+	var target struct {
+		Name  string
+		Ports []int
+	}
+	typed := &fileflag.FileJSON{Value: &target}
+	if err := typed.Set("@" + path); err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != "app" || !reflect.DeepEqual(target.Ports, []int{80, 443}) {
+		t.Errorf("target = %+v", target)
+	}
+
+	stdin := &fileflag.FileJSON{Stdin: strings.NewReader(`[1, "two"]`)}
+	if err := stdin.Set("-"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stdin.Value, []interface{}{1.0, "two"}) {
+		t.Errorf("Value from stdin = %#v", stdin.Value)
+	}
+
+	failed := &fileflag.FileJSON{}
+	err := failed.Set(invalid)
+	if err == nil || !strings.Contains(err.Error(), "is not a valid json file") {
+		t.Errorf("Set(invalid) error = %v", err)
+	}
+	if failed.HasChanged() || failed.Text != "" {
+		t.Error("failed Set changed the value")
+	}
+}
+
+func TestFileYAML(t *testing.T) {
+	path := writeFile(t, "config.yaml", "name: app\nports:\n  - 80\n  - 443\n")
+
+	var target struct {
+		Name  string `yaml:"name"`
+		Ports []int  `yaml:"ports"`
+	}
+	fv := &fileflag.FileYAML{Value: &target}
+	if err := fv.Set(path); err != nil {
+		t.Fatal(err)
+	}
+	if target.Name != "app" || !reflect.DeepEqual(target.Ports, []int{80, 443}) {
+		t.Errorf("target = %+v", target)
+	}
+	if got := fv.ValueString(); got != `{"Name":"app","Ports":[80,443]}` {
+		t.Errorf("ValueString = %s", got)
+	}
+
+	if err := (&fileflag.FileYAML{}).Set(writeFile(t, "invalid.yaml", "name: [")); err == nil {
+		t.Error("Set accepted invalid YAML")
+	}
+}
@@ -0,0 +1,97 @@
+package fileflag
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Dir is a `flag.Value` for directory path arguments.
+// The directory must exist, unless `Create` is set, in which case it is created with `Perm` (default 0755).
+// If `Readable` or `Writable` are set, the directory must be listable or allow creating files respectively.
+type Dir struct {
+	driver.Meta
+
+	Create   bool
+	Perm     os.FileMode
+	Readable bool
+	Writable bool
+
+	Value string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Dir) Help() string {
+	switch {
+	case fv.Readable && fv.Writable:
+		return "a readable and writable directory"
+	case fv.Readable:
+		return "a readable directory"
+	case fv.Writable:
+		return "a writable directory"
+	}
+	return "a directory"
+}
+
+// Set is flag.Value.Set
+func (fv *Dir) Set(v string) error {
+	info, err := os.Stat(v)
+	if os.IsNotExist(err) && fv.Create {
+		perm := fv.Perm
+		if perm == 0 {
+			perm = 0755
+		}
+		if err = os.MkdirAll(v, perm); err == nil {
+			info, err = os.Stat(v)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf(`"%s" must be a directory`, v)
+	}
+	if fv.Readable {
+		f, err := os.Open(v)
+		if err == nil {
+			_, err = f.Readdirnames(1)
+			f.Close()
+		}
+		if err != nil && err != io.EOF {
+			return fmt.Errorf(`"%s" must be readable: %v`, v, err)
+		}
+	}
+	if fv.Writable {
+		f, err := ioutil.TempFile(v, ".probe")
+		if err != nil {
+			return fmt.Errorf(`"%s" must be writable: %v`, v, err)
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+	fv.Value = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Dir) String() string {
+	return fv.Value
+}
+
+// ValueString returns the directory path.
+func (fv *Dir) ValueString() string {
+	return fv.Value
+}
+
+// ValueType returns the type name of the value.
+func (fv *Dir) ValueType() string {
+	return "dir"
+}
+
+// Type is pflag.Value.Type
+func (fv *Dir) Type() string {
+	return fv.ValueType()
+}
@@ -0,0 +1,62 @@
+package fileflag_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofunct/functional/flag/fileflag"
+)
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, "file", "")
+	tests := []struct {
+		name     string
+		fv       fileflag.Dir
+		arg      string
+		wantErr  bool
+		wantPerm os.FileMode
+	}{
+		{name: "existing", arg: dir},
+		{name: "readable and writable", fv: fileflag.Dir{Readable: true, Writable: true}, arg: dir},
+		{name: "missing", arg: filepath.Join(dir, "missing"), wantErr: true},
+		{name: "created", fv: fileflag.Dir{Create: true}, arg: filepath.Join(dir, "a", "b"), wantPerm: 0o755},
+		{name: "created with perm", fv: fileflag.Dir{Create: true, Perm: 0o700}, arg: filepath.Join(dir, "private"), wantPerm: 0o700},
+		{name: "file", arg: file, wantErr: true},
+		{name: "file with create", fv: fileflag.Dir{Create: true}, arg: file, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := tt.fv
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				if fv.HasChanged() || fv.Value != "" {
+					t.Error("failed Set changed the value")
+				}
+				return
+			}
+			if fv.Value != tt.arg || fv.String() != tt.arg {
+				t.Errorf("Value = %q, want %q", fv.Value, tt.arg)
+			}
+			if tt.wantPerm != 0 {
+				info, err := os.Stat(tt.arg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if perm := info.Mode().Perm() &^ 0o022; perm != tt.wantPerm&^0o022 {
+					t.Errorf("perm = %v, want %v", info.Mode().Perm(), tt.wantPerm)
+				}
+			}
+			if tt.fv.Writable {
+				entries, err := os.ReadDir(tt.arg)
+				if err != nil || len(entries) != 0 {
+					t.Errorf("Set left %d probe files behind (%v)", len(entries), err)
+				}
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@ package fileflag
 import (
 	"os"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // File is a `flag.Value` for file path arguments.
 // By default, any errors from os.Stat are returned.
 // Alternatively, the value of the `Validate` field is used as a validator when specified.
 type File struct {
+	driver.Meta
+
 	Validate func(os.FileInfo, error) error
 
 	Value string
@@ -19,7 +23,10 @@ func (fv *File) Set(v string) error {
 	info, err := os.Stat(v)
 	fv.Value = v
 	if fv.Validate != nil {
-		return fv.Validate(info, err)
+		err = fv.Validate(info, err)
+	}
+	if err == nil {
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -28,10 +35,27 @@ func (fv *File) String() string {
 	return fv.Value
 }
 
+// ValueString returns the file path.
+func (fv *File) ValueString() string {
+	return fv.Value
+}
+
+// ValueType returns the type name of the value.
+func (fv *File) ValueType() string {
+	return "file"
+}
+
+// Type is pflag.Value.Type
+func (fv *File) Type() string {
+	return fv.ValueType()
+}
+
 // Files is a `flag.Value` for file path arguments.
 // By default, any errors from os.Stat are returned.
 // Alternatively, the value of the `Validate` field is used as a validator when specified.
 type Files struct {
+	driver.Meta
+
 	Validate func(os.FileInfo, error) error
 
 	Values []string
@@ -42,7 +66,10 @@ func (fv *Files) Set(v string) error {
 	info, err := os.Stat(v)
 	fv.Values = append(fv.Values, v)
 	if fv.Validate != nil {
-		return fv.Validate(info, err)
+		err = fv.Validate(info, err)
+	}
+	if err == nil {
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -50,3 +77,18 @@ func (fv *Files) Set(v string) error {
 func (fv *Files) String() string {
 	return strings.Join(fv.Values, ",")
 }
+
+// ValueString returns the file paths.
+func (fv *Files) ValueString() string {
+	return strings.Join(fv.Values, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Files) ValueType() string {
+	return "fileSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Files) Type() string {
+	return fv.ValueType()
+}
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/gobwas/glob"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Glob is a `flag.Value` for glob syntax arguments.
@@ -13,6 +15,8 @@ import (
 // If `Separators` is non-nil, its elements are used as separators.
 // To have no separators, set `Separators` to a (non-nil) pointer to an empty slice.
 type Glob struct {
+	driver.Meta
+
 	Separators *[]rune
 
 	Value glob.Glob
@@ -46,6 +50,7 @@ func (fv *Glob) Set(v string) error {
 	}
 	fv.Text = v
 	fv.Value = g
+	fv.MarkChanged()
 	return nil
 }
 
@@ -53,11 +58,28 @@ func (fv *Glob) String() string {
 	return fv.Text
 }
 
+// ValueString returns the glob expression.
+func (fv *Glob) ValueString() string {
+	return fv.Text
+}
+
+// ValueType returns the type name of the value.
+func (fv *Glob) ValueType() string {
+	return "glob"
+}
+
+// Type is pflag.Value.Type
+func (fv *Glob) Type() string {
+	return fv.ValueType()
+}
+
 // Globs is a `flag.Value` for glob syntax arguments.
 // By default, `filepath.Separator` is used as a separator.
 // If `Separators` is non-nil, its elements are used as separators.
 // To have no separators, set `Separators` to a (non-nil) pointer to an empty slice.
 type Globs struct {
+	driver.Meta
+
 	Separators *[]rune
 
 	Values []glob.Glob
@@ -91,9 +113,25 @@ func (fv *Globs) Set(v string) error {
 	}
 	fv.Texts = append(fv.Texts, v)
 	fv.Values = append(fv.Values, g)
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *Globs) String() string {
 	return strings.Join(fv.Texts, ",")
 }
+
+// ValueString returns the glob expressions.
+func (fv *Globs) ValueString() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Globs) ValueType() string {
+	return "globSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Globs) Type() string {
+	return fv.ValueType()
+}
@@ -2,12 +2,17 @@ package fileflag
 
 import (
 	"fmt"
+	"strings"
 	"text/template"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Template is a `flag.Value` for `text.Template` arguments.
 // The value of the `Root` field is used as a root template when specified.
 type Template struct {
+	driver.Meta
+
 	Root *template.Template
 
 	Value *template.Template
@@ -27,7 +32,9 @@ func (fv *Template) Set(v string) error {
 	}
 	t, err := root.New(fmt.Sprintf("%T(%p)", fv, fv)).Parse(v)
 	if err == nil {
+		fv.Text = v
 		fv.Value = t
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -36,9 +43,26 @@ func (fv *Template) String() string {
 	return fv.Text
 }
 
+// ValueString returns the template text.
+func (fv *Template) ValueString() string {
+	return fv.Text
+}
+
+// ValueType returns the type name of the value.
+func (fv *Template) ValueType() string {
+	return "template"
+}
+
+// Type is pflag.Value.Type
+func (fv *Template) Type() string {
+	return fv.ValueType()
+}
+
 // Templates is a `flag.Value` for `text.Template` arguments.
 // The value of the `Root` field is used as a root template when specified.
 type Templates struct {
+	driver.Meta
+
 	Root *template.Template
 
 	Values []*template.Template
@@ -60,6 +84,7 @@ func (fv *Templates) Set(v string) error {
 	if err == nil {
 		fv.Texts = append(fv.Texts, v)
 		fv.Values = append(fv.Values, t)
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -67,3 +92,18 @@ func (fv *Templates) Set(v string) error {
 func (fv *Templates) String() string {
 	return fmt.Sprint(fv.Texts)
 }
+
+// ValueString returns the template texts.
+func (fv *Templates) ValueString() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Templates) ValueType() string {
+	return "templateSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Templates) Type() string {
+	return fv.ValueType()
+}
@@ -3,11 +3,15 @@ package mapflag
 import (
 	"fmt"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Map is a `flag.Value` for `KEY=VALUE` arguments.
 // The value of the `Separator` field is used instead  of `"="` when set.
 type Map struct {
+	driver.Meta
+
 	Separator string
 
 	Value struct {
@@ -44,9 +48,28 @@ func (fv *Map) Set(v string) error {
 		Key:   v[:i],
 		Value: v[i+len(separator):],
 	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *Map) String() string {
 	return fv.Text
 }
+
+// ValueString returns the parsed pair as KEY=VALUE.
+func (fv *Map) ValueString() string {
+	if fv.Value.Key == "" && fv.Value.Value == "" {
+		return ""
+	}
+	return fv.Value.Key + "=" + fv.Value.Value
+}
+
+// ValueType returns the type name of the value.
+func (fv *Map) ValueType() string {
+	return "map"
+}
+
+// Type is pflag.Value.Type
+func (fv *Map) Type() string {
+	return fv.ValueType()
+}
@@ -3,11 +3,15 @@ package mapflag
 import (
 	"fmt"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Maps is a `flag.Value` for `KEY=VALUE` arguments.
 // The value of the `Separator` field is used instead  of `"="` when set.
 type Maps struct {
+	driver.Meta
+
 	Separator string
 
 	Values []struct {
@@ -44,9 +48,29 @@ func (fv *Maps) Set(v string) error {
 		Key:   v[:i],
 		Value: v[i+len(separator):],
 	})
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *Maps) String() string {
 	return strings.Join(fv.Texts, ", ")
 }
+
+// ValueString returns the parsed pairs as KEY=VALUE.
+func (fv *Maps) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, v := range fv.Values {
+		texts[i] = v.Key + "=" + v.Value
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Maps) ValueType() string {
+	return "mapSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Maps) Type() string {
+	return fv.ValueType()
+}
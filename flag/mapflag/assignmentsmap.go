@@ -2,12 +2,17 @@ package mapflag
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // AssignmentsMap is a `flag.Value` for `KEY=VALUE` arguments.
 // The value of the `Separator` field is used instead  of `"="` when set.
 type AssignmentsMap struct {
+	driver.Meta
+
 	Separator string
 
 	Values map[string]string
@@ -38,9 +43,34 @@ func (fv *AssignmentsMap) Set(v string) error {
 		fv.Values = make(map[string]string)
 	}
 	fv.Values[v[:i]] = v[i+len(separator):]
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *AssignmentsMap) String() string {
 	return strings.Join(fv.Texts, ", ")
 }
+
+// ValueString returns the parsed pairs as KEY=VALUE, sorted by key.
+func (fv *AssignmentsMap) ValueString() string {
+	keys := make([]string, 0, len(fv.Values))
+	for k := range fv.Values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	texts := make([]string, len(keys))
+	for i, k := range keys {
+		texts[i] = k + "=" + fv.Values[k]
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *AssignmentsMap) ValueType() string {
+	return "stringToString"
+}
+
+// Type is pflag.Value.Type
+func (fv *AssignmentsMap) Type() string {
+	return fv.ValueType()
+}
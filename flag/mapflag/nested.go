@@ -0,0 +1,332 @@
+package mapflag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// maxListIndex bounds the list indexes accepted in keys, so a typo cannot allocate huge lists.
+const maxListIndex = 65536
+
+// NestedMap is a `flag.Value` for Helm-style `--set` arguments: comma-separated `KEY=VALUE`
+// assignments whose dotted keys build a nested `map[string]interface{}`.
+//
+// Keys are split at dots (`db.pool.max=10`) and may index lists (`servers[0].port=80`);
+// `\.` escapes a dot in a key and `\,` a comma in a value. Values are decoded as JSON
+// (`10`, `true`, `null`, `"quoted"`, `[1,2]`), then as YAML flow lists or maps (`[a, b]`),
+// and are otherwise kept as strings. If `Strings` is set, values are always kept as strings.
+// The values of all instances of the flag are merged, with later assignments winning.
+// The value of the `Separator` field is used instead of `"="` when set.
+type NestedMap struct {
+	driver.Meta
+
+	Separator string
+	Strings   bool
+
+	Value map[string]interface{}
+	Texts []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *NestedMap) Help() string {
+	separator := "="
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	if fv.Strings {
+		return fmt.Sprintf("comma-separated KEY%sVALUE assignments with dotted keys and string values", separator)
+	}
+	return fmt.Sprintf("comma-separated KEY%sVALUE assignments with dotted keys and JSON/YAML values", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *NestedMap) Set(v string) error {
+	separator := "="
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	value, _ := copyValue(fv.Value).(map[string]interface{})
+	if value == nil {
+		value = make(map[string]interface{})
+	}
+	for _, assignment := range splitAssignments(v, separator) {
+		i := strings.Index(assignment, separator)
+		if i < 0 {
+			return fmt.Errorf(`"%s" must have the form KEY%sVALUE`, assignment, separator)
+		}
+		path, err := parseKeyPath(assignment[:i])
+		if err != nil {
+			return err
+		}
+		var decoded interface{} = assignment[i+len(separator):]
+		if !fv.Strings {
+			decoded = decodeScalar(assignment[i+len(separator):])
+		}
+		value = setPath(value, path, decoded).(map[string]interface{})
+	}
+	fv.Value = value
+	fv.Texts = append(fv.Texts, v)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *NestedMap) String() string {
+	return strings.Join(fv.Texts, ", ")
+}
+
+// ValueString returns the nested map encoded as JSON.
+func (fv *NestedMap) ValueString() string {
+	if fv.Value == nil {
+		return ""
+	}
+	b, err := json.Marshal(fv.Value)
+	if err != nil {
+		return strings.Join(fv.Texts, ",")
+	}
+	return string(b)
+}
+
+// ValueType returns the type name of the value.
+func (fv *NestedMap) ValueType() string {
+	return "nestedMap"
+}
+
+// Type is pflag.Value.Type
+func (fv *NestedMap) Type() string {
+	return fv.ValueType()
+}
+
+// Merge deep-merges the nested map into dst and returns it, allocating dst if it is nil.
+// Nested maps are merged key by key; any other value in dst is replaced.
+func (fv *NestedMap) Merge(dst map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = make(map[string]interface{})
+	}
+	return mergeMaps(dst, fv.Value)
+}
+
+// Decode merges the nested map into the struct or map pointed to by v, using its JSON field names.
+// Fields without a corresponding key keep their current values.
+func (fv *NestedMap) Decode(v interface{}) error {
+	b, err := json.Marshal(fv.Value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func mergeMaps(dst, src map[string]interface{}) map[string]interface{} {
+	for k, v := range src {
+		srcMap, srcIsMap := v.(map[string]interface{})
+		dstMap, dstIsMap := dst[k].(map[string]interface{})
+		if srcIsMap {
+			if !dstIsMap {
+				dstMap = make(map[string]interface{}, len(srcMap))
+			}
+			dst[k] = mergeMaps(dstMap, srcMap)
+			continue
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// pathElem is one step of a key path: a map key, or a list index if index >= 0.
+type pathElem struct {
+	key   string
+	index int
+}
+
+// parseKeyPath splits a key such as `a.b[0].c` into its path elements.
+func parseKeyPath(key string) ([]pathElem, error) {
+	var path []pathElem
+	var b strings.Builder
+	flush := func() error {
+		if b.Len() == 0 {
+			return fmt.Errorf(`key "%s" has an empty element`, key)
+		}
+		path = append(path, pathElem{key: b.String(), index: -1})
+		b.Reset()
+		return nil
+	}
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c == '\\' && i+1 < len(key):
+			i++
+			b.WriteByte(key[i])
+		case c == '.':
+			if i > 0 && key[i-1] == ']' {
+				continue
+			}
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case c == '[':
+			if b.Len() > 0 {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			} else if len(path) == 0 {
+				return nil, fmt.Errorf(`key "%s" must not start with an index`, key)
+			}
+			j := strings.IndexByte(key[i:], ']')
+			if j < 0 {
+				return nil, fmt.Errorf(`key "%s" has an unterminated index`, key)
+			}
+			n, err := strconv.Atoi(key[i+1 : i+j])
+			if err != nil || n < 0 || n > maxListIndex {
+				return nil, fmt.Errorf(`key "%s" has an invalid index "%s"`, key, key[i+1:i+j])
+			}
+			path = append(path, pathElem{index: n})
+			i += j
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if b.Len() > 0 || len(path) == 0 || path[len(path)-1].index < 0 {
+		if err := flush(); err != nil {
+			return nil, err
+		}
+	}
+	return path, nil
+}
+
+// setPath sets the value at path below node, creating maps and lists as needed, and returns the updated node.
+func setPath(node interface{}, path []pathElem, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	elem := path[0]
+	if elem.index >= 0 {
+		list, _ := node.([]interface{})
+		for len(list) <= elem.index {
+			list = append(list, nil)
+		}
+		list[elem.index] = setPath(list[elem.index], path[1:], value)
+		return list
+	}
+	m, ok := node.(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+	}
+	m[elem.key] = setPath(m[elem.key], path[1:], value)
+	return m
+}
+
+// copyValue returns a deep copy of the maps and lists in v, so that a failed Set leaves the value unchanged.
+func copyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = copyValue(e)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, e := range v {
+			list[i] = copyValue(e)
+		}
+		return list
+	}
+	return v
+}
+
+// splitAssignments splits v at commas that are not escaped, quoted, or inside brackets or braces.
+// Quotes are only recognized at the start of a value, so `name=O'Brien,x=1` splits in two.
+// The escaping backslash of `\,` is removed.
+func splitAssignments(v, separator string) []string {
+	var parts []string
+	var b strings.Builder
+	depth := 0
+	var quote byte
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch {
+		case c == '\\' && i+1 < len(v) && v[i+1] == ',':
+			i++
+			b.WriteByte(',')
+			continue
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && depth == 0 && atValueStart(b.String(), separator):
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case (c == ']' || c == '}') && depth > 0:
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, b.String())
+			b.Reset()
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return append(parts, b.String())
+}
+
+// atValueStart returns whether the assignment read so far ends with its separator, ignoring spaces.
+func atValueStart(assignment, separator string) bool {
+	i := strings.Index(assignment, separator)
+	return i >= 0 && strings.TrimSpace(assignment[i+len(separator):]) == ""
+}
+
+// decodeScalar decodes v as JSON, then as a YAML flow list or map, falling back to the string itself.
+func decodeScalar(v string) interface{} {
+	trimmed := strings.TrimSpace(v)
+	if trimmed == "" {
+		return v
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(trimmed)))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err == nil && !decoder.More() {
+		return normalize(value)
+	}
+	if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+		if err := yaml.Unmarshal([]byte(trimmed), &value); err == nil {
+			return normalize(value)
+		}
+	}
+	return v
+}
+
+// normalize converts JSON numbers to int64 or float64 and YAML maps to `map[string]interface{}`.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case int:
+		return int64(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = normalize(v[k])
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+package mapflag_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofunct/functional/flag/mapflag"
+)
+
+func TestNestedMapSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{"apostrophe in value", []string{"name=O'Brien,x=1"}, map[string]interface{}{"name": "O'Brien", "x": int64(1)}, false},
+		{"quoted value", []string{`name="a,b",x=1`}, map[string]interface{}{"name": "a,b", "x": int64(1)}, false},
+		{"single-quoted value", []string{"name='a,b'"}, map[string]interface{}{"name": "'a,b'"}, false},
+		{"list index", []string{"s[0].port=80", "s[1].port=90"}, map[string]interface{}{"s": []interface{}{
+			map[string]interface{}{"port": int64(80)},
+			map[string]interface{}{"port": int64(90)},
+		}}, false},
+		{"failed set keeps value", []string{"a.b=1", "a.c=2,bad"}, map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := &mapflag.NestedMap{}
+			var err error
+			for _, arg := range tt.args {
+				if err = fv.Set(arg); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(fv.Value, tt.want) {
+				t.Errorf("Value = %#v, want %#v", fv.Value, tt.want)
+			}
+		})
+	}
+}
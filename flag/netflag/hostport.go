@@ -0,0 +1,212 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// HostPort is a `flag.Value` for host:port pairs.
+// The host is not resolved. The `DefaultPort` field is used when the port is omitted and it is set.
+type HostPort struct {
+	driver.Meta
+
+	DefaultPort int
+
+	Value HostPortValue
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *HostPort) Help() string {
+	return "a host:port pair"
+}
+
+// Set is flag.Value.Set
+func (fv *HostPort) Set(v string) error {
+	hostPort, err := parseHostPort(v, fv.DefaultPort)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = hostPort
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *HostPort) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed host and port.
+func (fv *HostPort) ValueString() string {
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *HostPort) ValueType() string {
+	return "hostPort"
+}
+
+// Type is pflag.Value.Type
+func (fv *HostPort) Type() string {
+	return fv.ValueType()
+}
+
+// HostPorts is a `flag.Value` for host:port pairs.
+// The host is not resolved. The `DefaultPort` field is used when the port is omitted and it is set.
+type HostPorts struct {
+	driver.Meta
+
+	DefaultPort int
+
+	Values []HostPortValue
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *HostPorts) Help() string {
+	return "a host:port pair"
+}
+
+// Set is flag.Value.Set
+func (fv *HostPorts) Set(v string) error {
+	hostPort, err := parseHostPort(v, fv.DefaultPort)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, hostPort)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *HostPorts) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed host and ports.
+func (fv *HostPorts) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, hostPort := range fv.Values {
+		texts[i] = hostPort.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *HostPorts) ValueType() string {
+	return "hostPortSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *HostPorts) Type() string {
+	return fv.ValueType()
+}
+
+// HostPortsCSV is a `flag.Value` for host:port pairs.
+// The host is not resolved. The `DefaultPort` field is used when the port is omitted and it is set.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type HostPortsCSV struct {
+	driver.Meta
+
+	DefaultPort int
+	Separator   string
+	Accumulate  bool
+
+	Values []HostPortValue
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *HostPortsCSV) Help() string {
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of host:port pairs", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *HostPortsCSV) Set(v string) error {
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		hostPort, err := parseHostPort(part, fv.DefaultPort)
+		if err != nil {
+			return err
+		}
+		fv.Texts = append(fv.Texts, part)
+		fv.Values = append(fv.Values, hostPort)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *HostPortsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed host and ports.
+func (fv *HostPortsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, hostPort := range fv.Values {
+		texts[i] = hostPort.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *HostPortsCSV) ValueType() string {
+	return "hostPortSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *HostPortsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// HostPortValue is an unresolved host and port.
+type HostPortValue struct {
+	Host string
+	Port int
+}
+
+// String returns the host and port joined by net.JoinHostPort, or an empty string for the zero value.
+func (hp HostPortValue) String() string {
+	if hp.Host == "" && hp.Port == 0 {
+		return ""
+	}
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+func parseHostPort(v string, defaultPort int) (HostPortValue, error) {
+	host, port, err := net.SplitHostPort(v)
+	if err != nil && defaultPort != 0 {
+		host = strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+		if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			return HostPortValue{}, fmt.Errorf(`not a valid host:port pair: "%s"`, v)
+		}
+		port, err = strconv.Itoa(defaultPort), nil
+	}
+	if err != nil {
+		return HostPortValue{}, fmt.Errorf(`not a valid host:port pair: "%s"`, v)
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPortValue{}, fmt.Errorf(`"%s" must have a port between 0 and 65535`, v)
+	}
+	return HostPortValue{Host: host, Port: int(n)}, nil
+}
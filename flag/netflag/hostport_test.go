@@ -0,0 +1,57 @@
+package netflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestHostPort(t *testing.T) {
+	tests := []struct {
+		arg         string
+		defaultPort int
+		want        netflag.HostPortValue
+		wantErr     bool
+	}{
+		{arg: "example.com:80", want: netflag.HostPortValue{Host: "example.com", Port: 80}},
+		{arg: ":8080", want: netflag.HostPortValue{Port: 8080}},
+		{arg: "[::1]:443", want: netflag.HostPortValue{Host: "::1", Port: 443}},
+		{arg: "example.com", defaultPort: 80, want: netflag.HostPortValue{Host: "example.com", Port: 80}},
+		{arg: "::1", defaultPort: 80, want: netflag.HostPortValue{Host: "::1", Port: 80}},
+		{arg: "[::1]", defaultPort: 80, want: netflag.HostPortValue{Host: "::1", Port: 80}},
+		{arg: "10.0.0.1:22", defaultPort: 80, want: netflag.HostPortValue{Host: "10.0.0.1", Port: 22}},
+		{arg: "example.com", wantErr: true},
+		{arg: "a:b:c", defaultPort: 80, wantErr: true},
+		{arg: "example.com:http", wantErr: true},
+		{arg: "example.com:65536", wantErr: true},
+		{arg: "example.com:-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.HostPort{DefaultPort: tt.defaultPort}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.Value != tt.want {
+				t.Errorf("Set(%q) = %+v, want %+v", tt.arg, fv.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostPortsCSV(t *testing.T) {
+	fv := &netflag.HostPortsCSV{DefaultPort: 80}
+	if err := fv.Set("a.example, [::1]:8080"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fv.ValueString(), "a.example:80,[::1]:8080"; got != want {
+		t.Errorf("ValueString = %q, want %q", got, want)
+	}
+	if err := fv.Set("b.example:81"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fv.ValueString(), "b.example:81"; got != want {
+		t.Errorf("ValueString after replacing = %q, want %q", got, want)
+	}
+}
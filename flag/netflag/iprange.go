@@ -0,0 +1,78 @@
+package netflag
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// IPRange is a `flag.Value` for IP address ranges such as 10.0.0.1-10.0.0.50.
+// A single IP address is accepted as a range of one address.
+type IPRange = typeflag.Value[IPRangeValue, ipRangeParser]
+
+// IPRanges is a `flag.Value` for IP address ranges such as 10.0.0.1-10.0.0.50.
+// A single IP address is accepted as a range of one address.
+type IPRanges = typeflag.List[IPRangeValue, ipRangeParser]
+
+// IPRangesCSV is a `flag.Value` for IP address ranges such as 10.0.0.1-10.0.0.50.
+// A single IP address is accepted as a range of one address.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type IPRangesCSV = typeflag.CSV[IPRangeValue, ipRangeParser]
+
+// IPRangeValue is an inclusive range of IP addresses of the same family.
+type IPRangeValue struct {
+	From net.IP
+	To   net.IP
+}
+
+// String returns the range as FROM-TO, or an empty string for the zero value.
+func (r IPRangeValue) String() string {
+	if r.From == nil {
+		return ""
+	}
+	return r.From.String() + "-" + r.To.String()
+}
+
+// Contains returns whether ip lies within the range.
+func (r IPRangeValue) Contains(ip net.IP) bool {
+	if r.From == nil || (ip.To4() == nil) != (r.From.To4() == nil) {
+		return false
+	}
+	ip = ip.To16()
+	return bytes.Compare(ip, r.From.To16()) >= 0 && bytes.Compare(ip, r.To.To16()) <= 0
+}
+
+type ipRangeParser struct{}
+
+func (ipRangeParser) Parse(v string) (IPRangeValue, error) {
+	fromText, toText := v, v
+	if i := strings.Index(v, "-"); i >= 0 {
+		fromText, toText = strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+1:])
+	}
+	from, to := net.ParseIP(fromText), net.ParseIP(toText)
+	if from == nil || to == nil {
+		return IPRangeValue{}, fmt.Errorf(`not a valid IP address range: "%s"`, v)
+	}
+	if (from.To4() == nil) != (to.To4() == nil) {
+		return IPRangeValue{}, fmt.Errorf(`"%s" must not mix IPv4 and IPv6 addresses`, v)
+	}
+	if bytes.Compare(from.To16(), to.To16()) > 0 {
+		return IPRangeValue{}, fmt.Errorf(`"%s" must not end before it starts`, v)
+	}
+	return IPRangeValue{From: from, To: to}, nil
+}
+
+func (ipRangeParser) Format(r IPRangeValue) string { return r.String() }
+
+func (ipRangeParser) Describe(plural bool) string {
+	if plural {
+		return "IP address ranges such as 10.0.0.1-10.0.0.50"
+	}
+	return "an IP address range"
+}
+
+func (ipRangeParser) TypeName() string { return "ipRange" }
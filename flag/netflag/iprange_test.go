@@ -0,0 +1,64 @@
+package netflag_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestIPRange(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{arg: "10.0.0.1-10.0.0.50", want: "10.0.0.1-10.0.0.50"},
+		{arg: "10.0.0.1 - 10.0.0.50", want: "10.0.0.1-10.0.0.50"},
+		{arg: "10.0.0.7", want: "10.0.0.7-10.0.0.7"},
+		{arg: "2001:db8::1-2001:db8::ff", want: "2001:db8::1-2001:db8::ff"},
+		{arg: "10.0.0.50-10.0.0.1", wantErr: true},
+		{arg: "10.0.0.1-2001:db8::1", wantErr: true},
+		{arg: "10.0.0.1-", wantErr: true},
+		{arg: "10.0.0.0/8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.IPRange{}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.ValueString() != tt.want {
+				t.Errorf("Set(%q) = %s, want %s", tt.arg, fv.ValueString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIPRangeContains(t *testing.T) {
+	fv := &netflag.IPRange{}
+	if err := fv.Set("10.0.0.10-10.0.1.5"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.0.0.9", false},
+		{"10.0.0.10", true},
+		{"10.0.0.255", true},
+		{"10.0.1.5", true},
+		{"10.0.1.6", false},
+		{"::ffff:10.0.0.20", true},
+		{"2001:db8::1", false},
+	}
+	for _, tt := range tests {
+		if got := fv.Value.Contains(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+	if (netflag.IPRangeValue{}).Contains(net.ParseIP("10.0.0.1")) {
+		t.Error("the zero range contains an address")
+	}
+}
@@ -0,0 +1,33 @@
+package netflag
+
+import (
+	"net"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// HardwareAddr is a `flag.Value` for MAC addresses.
+type HardwareAddr = typeflag.Value[net.HardwareAddr, macParser]
+
+// HardwareAddrs is a `flag.Value` for MAC addresses.
+type HardwareAddrs = typeflag.List[net.HardwareAddr, macParser]
+
+// HardwareAddrsCSV is a `flag.Value` for MAC addresses.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type HardwareAddrsCSV = typeflag.CSV[net.HardwareAddr, macParser]
+
+type macParser struct{}
+
+func (macParser) Parse(v string) (net.HardwareAddr, error) { return net.ParseMAC(v) }
+
+func (macParser) Format(mac net.HardwareAddr) string { return mac.String() }
+
+func (macParser) Describe(plural bool) string {
+	if plural {
+		return "MAC addresses"
+	}
+	return "a MAC address"
+}
+
+func (macParser) TypeName() string { return "mac" }
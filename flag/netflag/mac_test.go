@@ -0,0 +1,44 @@
+package netflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestHardwareAddr(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{arg: "00:00:5e:00:53:01", want: "00:00:5e:00:53:01"},
+		{arg: "00-00-5E-00-53-01", want: "00:00:5e:00:53:01"},
+		{arg: "0000.5e00.5301", want: "00:00:5e:00:53:01"},
+		{arg: "02:00:5e:10:00:00:00:01", want: "02:00:5e:10:00:00:00:01"},
+		{arg: "00:00:5e:00:53", wantErr: true},
+		{arg: "00:00:5e:00:53:zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.HardwareAddr{}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.ValueString() != tt.want {
+				t.Errorf("Set(%q) = %s, want %s", tt.arg, fv.ValueString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestHardwareAddrsCSV(t *testing.T) {
+	fv := &netflag.HardwareAddrsCSV{Unique: true}
+	if err := fv.Set("00:00:5e:00:53:01,00-00-5E-00-53-01,00:00:5e:00:53:02"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := fv.ValueString(), "00:00:5e:00:53:01,00:00:5e:00:53:02"; got != want {
+		t.Errorf("ValueString = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,405 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Port is a `flag.Value` for port numbers or service names.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+type Port struct {
+	driver.Meta
+
+	Network string
+
+	Value int
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Port) Help() string {
+	return "a port number or service name"
+}
+
+// Set is flag.Value.Set
+func (fv *Port) Set(v string) error {
+	port, err := parsePort(network(fv.Network, "tcp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = port
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Port) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed port number.
+func (fv *Port) ValueString() string {
+	return strconv.Itoa(fv.Value)
+}
+
+// ValueType returns the type name of the value.
+func (fv *Port) ValueType() string {
+	return "port"
+}
+
+// Type is pflag.Value.Type
+func (fv *Port) Type() string {
+	return fv.ValueType()
+}
+
+// Ports is a `flag.Value` for port numbers or service names.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+type Ports struct {
+	driver.Meta
+
+	Network string
+
+	Values []int
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Ports) Help() string {
+	return "a port number or service name"
+}
+
+// Set is flag.Value.Set
+func (fv *Ports) Set(v string) error {
+	port, err := parsePort(network(fv.Network, "tcp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, port)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Ports) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed port numbers.
+func (fv *Ports) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, port := range fv.Values {
+		texts[i] = strconv.Itoa(port)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Ports) ValueType() string {
+	return "portSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Ports) Type() string {
+	return fv.ValueType()
+}
+
+// PortsCSV is a `flag.Value` for port numbers or service names.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type PortsCSV struct {
+	driver.Meta
+
+	Network    string
+	Separator  string
+	Accumulate bool
+
+	Values []int
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *PortsCSV) Help() string {
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of port numbers or service names", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *PortsCSV) Set(v string) error {
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		port, err := parsePort(network(fv.Network, "tcp"), part)
+		if err != nil {
+			return err
+		}
+		fv.Texts = append(fv.Texts, part)
+		fv.Values = append(fv.Values, port)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *PortsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed port numbers.
+func (fv *PortsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, port := range fv.Values {
+		texts[i] = strconv.Itoa(port)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *PortsCSV) ValueType() string {
+	return "portSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *PortsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// PortRange is a `flag.Value` for port ranges such as 8000-8080.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+// A single port is accepted as a range of one port.
+type PortRange struct {
+	driver.Meta
+
+	Network string
+
+	Value PortRangeValue
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *PortRange) Help() string {
+	return "a port range"
+}
+
+// Set is flag.Value.Set
+func (fv *PortRange) Set(v string) error {
+	r, err := parsePortRange(network(fv.Network, "tcp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = r
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *PortRange) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed port range.
+func (fv *PortRange) ValueString() string {
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *PortRange) ValueType() string {
+	return "portRange"
+}
+
+// Type is pflag.Value.Type
+func (fv *PortRange) Type() string {
+	return fv.ValueType()
+}
+
+// PortRanges is a `flag.Value` for port ranges such as 8000-8080.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+// A single port is accepted as a range of one port.
+type PortRanges struct {
+	driver.Meta
+
+	Network string
+
+	Values []PortRangeValue
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *PortRanges) Help() string {
+	return "a port range"
+}
+
+// Set is flag.Value.Set
+func (fv *PortRanges) Set(v string) error {
+	r, err := parsePortRange(network(fv.Network, "tcp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, r)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *PortRanges) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed port ranges.
+func (fv *PortRanges) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, r := range fv.Values {
+		texts[i] = r.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *PortRanges) ValueType() string {
+	return "portRangeSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *PortRanges) Type() string {
+	return fv.ValueType()
+}
+
+// PortRangesCSV is a `flag.Value` for port ranges such as 8000-8080.
+// The `Network` field is used if set, otherwise "tcp". It is used to look up service names.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+// A single port is accepted as a range of one port.
+type PortRangesCSV struct {
+	driver.Meta
+
+	Network    string
+	Separator  string
+	Accumulate bool
+
+	Values []PortRangeValue
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *PortRangesCSV) Help() string {
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of port ranges such as 8000-8080", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *PortRangesCSV) Set(v string) error {
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		r, err := parsePortRange(network(fv.Network, "tcp"), part)
+		if err != nil {
+			return err
+		}
+		fv.Texts = append(fv.Texts, part)
+		fv.Values = append(fv.Values, r)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *PortRangesCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed port ranges.
+func (fv *PortRangesCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, r := range fv.Values {
+		texts[i] = r.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *PortRangesCSV) ValueType() string {
+	return "portRangeSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *PortRangesCSV) Type() string {
+	return fv.ValueType()
+}
+
+// Contains returns whether port lies within the range.
+func (fv *PortRange) Contains(port int) bool {
+	return fv.Value.Contains(port)
+}
+
+// PortRangeValue is an inclusive range of ports.
+type PortRangeValue struct {
+	From int
+	To   int
+}
+
+// String returns the range as FROM-TO, or a single port if both ends are equal.
+func (r PortRangeValue) String() string {
+	if r.From == r.To {
+		return strconv.Itoa(r.From)
+	}
+	return strconv.Itoa(r.From) + "-" + strconv.Itoa(r.To)
+}
+
+// Contains returns whether port lies within the range.
+func (r PortRangeValue) Contains(port int) bool {
+	return port >= r.From && port <= r.To
+}
+
+func parsePort(network, v string) (int, error) {
+	if n, err := strconv.ParseUint(v, 10, 16); err == nil {
+		return int(n), nil
+	}
+	port, err := net.LookupPort(network, v)
+	if err != nil {
+		return 0, fmt.Errorf(`not a valid port: "%s"`, v)
+	}
+	return port, nil
+}
+
+func parsePortRange(network, v string) (PortRangeValue, error) {
+	fromText, toText := v, v
+	if i := strings.Index(v, "-"); i >= 0 {
+		fromText, toText = strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+1:])
+	}
+	from, err := parsePort(network, fromText)
+	if err != nil {
+		return PortRangeValue{}, err
+	}
+	to, err := parsePort(network, toText)
+	if err != nil {
+		return PortRangeValue{}, err
+	}
+	if from > to {
+		return PortRangeValue{}, fmt.Errorf(`"%s" must not end before it starts`, v)
+	}
+	return PortRangeValue{From: from, To: to}, nil
+}
@@ -0,0 +1,72 @@
+package netflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestPort(t *testing.T) {
+	tests := []struct {
+		arg     string
+		network string
+		want    int
+		wantErr bool
+	}{
+		{arg: "8080", want: 8080},
+		{arg: "0", want: 0},
+		{arg: "65535", want: 65535},
+		{arg: "https", want: 443},
+		{arg: "domain", network: "udp", want: 53},
+		{arg: "65536", wantErr: true},
+		{arg: "-1", wantErr: true},
+		{arg: "no-such-service", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.Port{Network: tt.network}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.Value != tt.want {
+				t.Errorf("Set(%q) = %d, want %d", tt.arg, fv.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestPortRange(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{arg: "8000-8080", want: "8000-8080"},
+		{arg: "8000 - 8080", want: "8000-8080"},
+		{arg: "22", want: "22"},
+		{arg: "http-https", want: "80-443"},
+		{arg: "8080-8000", wantErr: true},
+		{arg: "8000-", wantErr: true},
+		{arg: "1-70000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.PortRange{}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.ValueString() != tt.want {
+				t.Errorf("Set(%q) = %s, want %s", tt.arg, fv.ValueString(), tt.want)
+			}
+		})
+	}
+
+	r := netflag.PortRangeValue{From: 8000, To: 8080}
+	for port, want := range map[int]bool{7999: false, 8000: true, 8080: true, 8081: false} {
+		if got := r.Contains(port); got != want {
+			t.Errorf("Contains(%d) = %v, want %v", port, got, want)
+		}
+	}
+}
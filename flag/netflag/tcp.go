@@ -1,16 +1,18 @@
 package netflag
 
-
 import (
-	"fmt"
+	"net"
 	"strings"
 
-	"net"
+	"github.com/gofunct/functional/flag/driver"
+	"github.com/gofunct/functional/flag/typeflag"
 )
 
 // TCPAddr is a `flag.Value` for TCP addresses.
 // The `Network` field is used if set, otherwise "tcp".
 type TCPAddr struct {
+	driver.Meta
+
 	Network string
 
 	Value *net.TCPAddr
@@ -19,21 +21,18 @@ type TCPAddr struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *TCPAddr) Help() string {
-	return "a TCP address"
+	return tcpParser{fv.Network}.Describe(false)
 }
 
 // Set is flag.Value.Set
 func (fv *TCPAddr) Set(v string) error {
-	network := "tcp"
-	if fv.Network != "" {
-		network = fv.Network
-	}
-	tcpAddr, err := net.ResolveTCPAddr(network, v)
+	tcpAddr, err := tcpParser{fv.Network}.Parse(v)
 	if err != nil {
 		return err
 	}
 	fv.Text = v
 	fv.Value = tcpAddr
+	fv.MarkChanged()
 	return nil
 }
 
@@ -41,9 +40,29 @@ func (fv *TCPAddr) String() string {
 	return fv.Text
 }
 
+// ValueString returns the resolved TCP address.
+func (fv *TCPAddr) ValueString() string {
+	if fv.Value == nil {
+		return ""
+	}
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *TCPAddr) ValueType() string {
+	return "tcpAddr"
+}
+
+// Type is pflag.Value.Type
+func (fv *TCPAddr) Type() string {
+	return fv.ValueType()
+}
+
 // TCPAddrs is a `flag.Value` for TCPAddr addresses.
 // The `Network` field is used if set, otherwise "tcp".
 type TCPAddrs struct {
+	driver.Meta
+
 	Network string
 
 	Values []*net.TCPAddr
@@ -52,21 +71,17 @@ type TCPAddrs struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *TCPAddrs) Help() string {
-	return "a TCP address"
+	return tcpParser{fv.Network}.Describe(false)
 }
 
 // Set is flag.Value.Set
 func (fv *TCPAddrs) Set(v string) error {
-	network := "tcp"
-	if fv.Network != "" {
-		network = fv.Network
-	}
-	tcpAddr, err := net.ResolveTCPAddr(network, v)
+	values, texts, err := typeflag.Append[*net.TCPAddr](tcpParser{fv.Network}, fv.Values, fv.Texts, v, false, nil)
 	if err != nil {
 		return err
 	}
-	fv.Texts = append(fv.Texts, v)
-	fv.Values = append(fv.Values, tcpAddr)
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
 	return nil
 }
 
@@ -74,11 +89,28 @@ func (fv *TCPAddrs) String() string {
 	return strings.Join(fv.Texts, ",")
 }
 
+// ValueString returns the resolved TCP addresses.
+func (fv *TCPAddrs) ValueString() string {
+	return typeflag.FormatValues[*net.TCPAddr](tcpParser{}, fv.Values)
+}
+
+// ValueType returns the type name of the value.
+func (fv *TCPAddrs) ValueType() string {
+	return "tcpAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *TCPAddrs) Type() string {
+	return fv.ValueType()
+}
+
 // TCPAddrsCSV is a `flag.Value` for TCPAddr addresses.
 // The `Network` field is used if set, otherwise "tcp".
 // If `Accumulate` is set, the values of all instances of the flag are accumulated.
 // The `Separator` field is used instead of the comma when set.
 type TCPAddrsCSV struct {
+	driver.Meta
+
 	Network    string
 	Separator  string
 	Accumulate bool
@@ -89,40 +121,59 @@ type TCPAddrsCSV struct {
 
 // Help returns a string suitable for inclusion in a flag help message.
 func (fv *TCPAddrsCSV) Help() string {
-	separator := ","
-	if fv.Separator != "" {
-		separator = fv.Separator
-	}
-	return fmt.Sprintf("%q-separated list of TCP addresses", separator)
+	return typeflag.CSVHelp[*net.TCPAddr](tcpParser{fv.Network}, fv.Separator)
 }
 
 // Set is flag.Value.Set
 func (fv *TCPAddrsCSV) Set(v string) error {
-	network := "tcp"
-	if fv.Network != "" {
-		network = fv.Network
-	}
-	separator := fv.Separator
-	if separator == "" {
-		separator = ","
-	}
+	values, texts := fv.Values, fv.Texts
 	if !fv.Accumulate {
-		fv.Values = fv.Values[:0]
-		fv.Texts = fv.Texts[:0]
+		values, texts = nil, nil
 	}
-	parts := strings.Split(v, separator)
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		tcpAddr, err := net.ResolveTCPAddr(network, part)
-		if err != nil {
-			return err
-		}
-		fv.Texts = append(fv.Texts, part)
-		fv.Values = append(fv.Values, tcpAddr)
+	values, texts, err := typeflag.AppendCSV[*net.TCPAddr](tcpParser{fv.Network}, values, texts, v, fv.Separator, false, nil)
+	if err != nil {
+		return err
 	}
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *TCPAddrsCSV) String() string {
 	return strings.Join(fv.Texts, ",")
-}
\ No newline at end of file
+}
+
+// ValueString returns the resolved TCP addresses.
+func (fv *TCPAddrsCSV) ValueString() string {
+	return typeflag.FormatValues[*net.TCPAddr](tcpParser{}, fv.Values)
+}
+
+// ValueType returns the type name of the value.
+func (fv *TCPAddrsCSV) ValueType() string {
+	return "tcpAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *TCPAddrsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// tcpParser is the typeflag.Parser resolving TCP addresses on a network, "tcp" by default.
+type tcpParser struct {
+	network string
+}
+
+func (p tcpParser) Parse(v string) (*net.TCPAddr, error) {
+	return net.ResolveTCPAddr(network(p.network, "tcp"), v)
+}
+
+func (p tcpParser) Format(addr *net.TCPAddr) string { return addr.String() }
+
+func (p tcpParser) Describe(plural bool) string {
+	if plural {
+		return "TCP addresses"
+	}
+	return "a TCP address"
+}
+
+func (p tcpParser) TypeName() string { return "tcpAddr" }
@@ -0,0 +1,189 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// UDPAddr is a `flag.Value` for UDP addresses.
+// The `Network` field is used if set, otherwise "udp".
+type UDPAddr struct {
+	driver.Meta
+
+	Network string
+
+	Value *net.UDPAddr
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UDPAddr) Help() string {
+	return "a UDP address"
+}
+
+// Set is flag.Value.Set
+func (fv *UDPAddr) Set(v string) error {
+	udpAddr, err := net.ResolveUDPAddr(network(fv.Network, "udp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = udpAddr
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UDPAddr) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed UDP address.
+func (fv *UDPAddr) ValueString() string {
+	if fv.Value == nil {
+		return ""
+	}
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *UDPAddr) ValueType() string {
+	return "udpAddr"
+}
+
+// Type is pflag.Value.Type
+func (fv *UDPAddr) Type() string {
+	return fv.ValueType()
+}
+
+// UDPAddrs is a `flag.Value` for UDP addresses.
+// The `Network` field is used if set, otherwise "udp".
+type UDPAddrs struct {
+	driver.Meta
+
+	Network string
+
+	Values []*net.UDPAddr
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UDPAddrs) Help() string {
+	return "a UDP address"
+}
+
+// Set is flag.Value.Set
+func (fv *UDPAddrs) Set(v string) error {
+	udpAddr, err := net.ResolveUDPAddr(network(fv.Network, "udp"), v)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, udpAddr)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UDPAddrs) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed UDP addresses.
+func (fv *UDPAddrs) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, udpAddr := range fv.Values {
+		texts[i] = udpAddr.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *UDPAddrs) ValueType() string {
+	return "udpAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *UDPAddrs) Type() string {
+	return fv.ValueType()
+}
+
+// UDPAddrsCSV is a `flag.Value` for UDP addresses.
+// The `Network` field is used if set, otherwise "udp".
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type UDPAddrsCSV struct {
+	driver.Meta
+
+	Network    string
+	Separator  string
+	Accumulate bool
+
+	Values []*net.UDPAddr
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UDPAddrsCSV) Help() string {
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of UDP addresses", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *UDPAddrsCSV) Set(v string) error {
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		udpAddr, err := net.ResolveUDPAddr(network(fv.Network, "udp"), part)
+		if err != nil {
+			return err
+		}
+		fv.Texts = append(fv.Texts, part)
+		fv.Values = append(fv.Values, udpAddr)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UDPAddrsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed UDP addresses.
+func (fv *UDPAddrsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, udpAddr := range fv.Values {
+		texts[i] = udpAddr.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *UDPAddrsCSV) ValueType() string {
+	return "udpAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *UDPAddrsCSV) Type() string {
+	return fv.ValueType()
+}
+
+// network returns n, or def if n is empty.
+func network(n, def string) string {
+	if n == "" {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,48 @@
+package netflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestUDPAddr(t *testing.T) {
+	tests := []struct {
+		arg     string
+		network string
+		want    string
+		wantErr bool
+	}{
+		{arg: "127.0.0.1:53", want: "127.0.0.1:53"},
+		{arg: ":514", want: ":514"},
+		{arg: "[::1]:53", want: "[::1]:53"},
+		{arg: "127.0.0.1:domain", want: "127.0.0.1:53"},
+		{arg: "[::1]:53", network: "udp4", wantErr: true},
+		{arg: "127.0.0.1", wantErr: true},
+		{arg: "127.0.0.1:99999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &netflag.UDPAddr{Network: tt.network}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && fv.ValueString() != tt.want {
+				t.Errorf("Set(%q) = %s, want %s", tt.arg, fv.ValueString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUDPAddrsCSV(t *testing.T) {
+	fv := &netflag.UDPAddrsCSV{Separator: ";", Accumulate: true}
+	for _, arg := range []string{"127.0.0.1:53; [::1]:53", ":514"} {
+		if err := fv.Set(arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := fv.ValueString(), "127.0.0.1:53,[::1]:53,:514"; got != want {
+		t.Errorf("ValueString = %q, want %q", got, want)
+	}
+}
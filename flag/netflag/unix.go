@@ -0,0 +1,181 @@
+package netflag
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// UnixAddr is a `flag.Value` for Unix socket addresses.
+// The `Network` field is used if set, otherwise "unix". Valid networks are "unix", "unixgram" and "unixpacket".
+type UnixAddr struct {
+	driver.Meta
+
+	Network string
+
+	Value *net.UnixAddr
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UnixAddr) Help() string {
+	return "a Unix socket address"
+}
+
+// Set is flag.Value.Set
+func (fv *UnixAddr) Set(v string) error {
+	unixAddr, err := net.ResolveUnixAddr(network(fv.Network, "unix"), v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = unixAddr
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UnixAddr) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed socket address.
+func (fv *UnixAddr) ValueString() string {
+	if fv.Value == nil {
+		return ""
+	}
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *UnixAddr) ValueType() string {
+	return "unixAddr"
+}
+
+// Type is pflag.Value.Type
+func (fv *UnixAddr) Type() string {
+	return fv.ValueType()
+}
+
+// UnixAddrs is a `flag.Value` for Unix socket addresses.
+// The `Network` field is used if set, otherwise "unix". Valid networks are "unix", "unixgram" and "unixpacket".
+type UnixAddrs struct {
+	driver.Meta
+
+	Network string
+
+	Values []*net.UnixAddr
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UnixAddrs) Help() string {
+	return "a Unix socket address"
+}
+
+// Set is flag.Value.Set
+func (fv *UnixAddrs) Set(v string) error {
+	unixAddr, err := net.ResolveUnixAddr(network(fv.Network, "unix"), v)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, unixAddr)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UnixAddrs) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed socket addresses.
+func (fv *UnixAddrs) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, unixAddr := range fv.Values {
+		texts[i] = unixAddr.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *UnixAddrs) ValueType() string {
+	return "unixAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *UnixAddrs) Type() string {
+	return fv.ValueType()
+}
+
+// UnixAddrsCSV is a `flag.Value` for Unix socket addresses.
+// The `Network` field is used if set, otherwise "unix". Valid networks are "unix", "unixgram" and "unixpacket".
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type UnixAddrsCSV struct {
+	driver.Meta
+
+	Network    string
+	Separator  string
+	Accumulate bool
+
+	Values []*net.UnixAddr
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UnixAddrsCSV) Help() string {
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of Unix socket addresses", separator)
+}
+
+// Set is flag.Value.Set
+func (fv *UnixAddrsCSV) Set(v string) error {
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		unixAddr, err := net.ResolveUnixAddr(network(fv.Network, "unix"), part)
+		if err != nil {
+			return err
+		}
+		fv.Texts = append(fv.Texts, part)
+		fv.Values = append(fv.Values, unixAddr)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UnixAddrsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed socket addresses.
+func (fv *UnixAddrsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, unixAddr := range fv.Values {
+		texts[i] = unixAddr.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *UnixAddrsCSV) ValueType() string {
+	return "unixAddrSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *UnixAddrsCSV) Type() string {
+	return fv.ValueType()
+}
@@ -0,0 +1,37 @@
+package netflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/netflag"
+)
+
+func TestUnixAddr(t *testing.T) {
+	tests := []struct {
+		arg         string
+		network     string
+		wantNetwork string
+		wantErr     bool
+	}{
+		{arg: "/run/app.sock", wantNetwork: "unix"},
+		{arg: "@abstract", wantNetwork: "unix"},
+		{arg: "/run/app.sock", network: "unixgram", wantNetwork: "unixgram"},
+		{arg: "/run/app.sock", network: "unixpacket", wantNetwork: "unixpacket"},
+		{arg: "/run/app.sock", network: "tcp", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.network+" "+tt.arg, func(t *testing.T) {
+			fv := &netflag.UnixAddr{Network: tt.network}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fv.Value.Name != tt.arg || fv.Value.Net != tt.wantNetwork || fv.ValueString() != tt.arg {
+				t.Errorf("Set(%q) = %s %s", tt.arg, fv.Value.Net, fv.Value.Name)
+			}
+		})
+	}
+}
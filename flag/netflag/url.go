@@ -2,55 +2,32 @@ package netflag
 
 import (
 	"net/url"
-	"strings"
+
+	"github.com/gofunct/functional/flag/typeflag"
 )
 
 // URL is a `flag.Value` for `url.URL` arguments.
-type URL struct {
-	Value *url.URL
-	Text  string
-}
+type URL = typeflag.Value[*url.URL, urlParser]
 
-// Help returns a string suitable for inclusion in a flag help message.
-func (fv *URL) Help() string {
-	return "a URL"
-}
+// URLs is a `flag.Value` for `url.URL` arguments.
+type URLs = typeflag.List[*url.URL, urlParser]
 
-// Set is flag.Value.Set
-func (fv *URL) Set(v string) error {
-	u, err := url.Parse(v)
-	if err == nil {
-		fv.Text = v
-		fv.Value = u
-	}
-	return err
-}
+// URLsCSV is a `flag.Value` for `url.URL` arguments.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type URLsCSV = typeflag.CSV[*url.URL, urlParser]
 
-func (fv *URL) String() string {
-	return fv.Text
-}
+type urlParser struct{}
 
-// URLs is a `flag.Value` for `url.URL` arguments.
-type URLs struct {
-	Values []*url.URL
-	Texts  []string
-}
+func (urlParser) Parse(v string) (*url.URL, error) { return url.Parse(v) }
 
-// Help returns a string suitable for inclusion in a flag help message.
-func (fv *URLs) Help() string {
-	return "a URL"
-}
+func (urlParser) Format(u *url.URL) string { return u.String() }
 
-// Set is flag.Value.Set
-func (fv *URLs) Set(v string) error {
-	u, err := url.Parse(v)
-	if err == nil {
-		fv.Texts = append(fv.Texts, v)
-		fv.Values = append(fv.Values, u)
+func (urlParser) Describe(plural bool) string {
+	if plural {
+		return "URLs"
 	}
-	return err
+	return "a URL"
 }
 
-func (fv *URLs) String() string {
-	return strings.Join(fv.Texts, ",")
-}
\ No newline at end of file
+func (urlParser) TypeName() string { return "url" }
@@ -0,0 +1,308 @@
+package flag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/encoding"
+	"github.com/gofunct/functional/flag/driver"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// Resolver fills in flags that were not given on the command line,
+// first from environment variables and then from a config file.
+//
+// The environment variable for a flag is derived from `EnvPrefix` and the flag name,
+// e.g. the flag "log-level" with prefix "APP" is read from `APP_LOG_LEVEL`.
+// The config file is decoded with the decoder for its extension (yaml, yml, json or toml)
+// from `Decoders`, which defaults to `encoding.DefaultDecoders`. A flag is looked up by its name,
+// and then as a path of nested keys separated by "." (or "-", as used by `Bind` for nested structs).
+type Resolver struct {
+	EnvPrefix  string
+	ConfigFile string
+	Decoders   encoding.DecoderGroup
+
+	sources map[string]driver.Source
+	values  map[string]string
+}
+
+// EnvName returns the environment variable name for the flag name with the given prefix.
+func EnvName(prefix, name string) string {
+	name = strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	if prefix == "" {
+		return name
+	}
+	return strings.ToUpper(strings.TrimSuffix(prefix, "_")) + "_" + name
+}
+
+// Resolve records the flags set on the command line of the already-parsed fs,
+// and sets the remaining ones from the environment or the config file.
+// When the flags were registered with `Bind`, call `Binding.Apply` afterwards to copy the results.
+//
+// Calling Resolve again keeps the values set by the previous call, and their sources,
+// unless the flag set changed them since.
+func (r *Resolver) Resolve(fs *flag.FlagSet) error {
+	previous, values := r.sources, r.values
+	r.sources = make(map[string]driver.Source)
+	r.values = make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if source, ok := previousSource(f, previous, values); ok {
+			r.record(f, source)
+			return
+		}
+		r.record(f, driver.SourceFlag)
+	})
+
+	config, err := r.readConfig()
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if _, ok := r.sources[f.Name]; ok {
+			return
+		}
+		if v, ok := os.LookupEnv(EnvName(r.EnvPrefix, f.Name)); ok {
+			if err := fs.Set(f.Name, v); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid value %q for flag -%s from $%s: %v", redactValue(f, v), f.Name, EnvName(r.EnvPrefix, f.Name), err))
+				return
+			}
+			r.record(f, driver.SourceEnv)
+			return
+		}
+		if v, ok := lookupConfig(config, f.Name); ok {
+			if err := setFromConfig(fs, f, v); err != nil {
+				errs = append(errs, fmt.Sprintf("invalid value %v for flag -%s from %s: %v", redactValue(f, v), f.Name, r.ConfigFile, err))
+				return
+			}
+			r.record(f, driver.SourceConfig)
+			return
+		}
+		r.sources[f.Name] = driver.SourceDefault
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("flag: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Source returns where the value of the named flag came from during the last `Resolve`.
+func (r *Resolver) Source(name string) driver.Source {
+	if s, ok := r.sources[name]; ok {
+		return s
+	}
+	return driver.SourceDefault
+}
+
+func (r *Resolver) record(f *flag.Flag, source driver.Source) {
+	r.sources[f.Name] = source
+	if s, ok := f.Value.(interface {
+		SetSource(driver.Source)
+	}); ok {
+		s.SetSource(source)
+	} else if source != driver.SourceFlag {
+		r.values[f.Name] = f.Value.String()
+	}
+}
+
+// previousSource returns the source of f recorded by the previous `Resolve`, if it set
+// the value from the environment or the config file and the flag set has not changed it since.
+// Values recording their source forget it when set; the text of other values is compared.
+func previousSource(f *flag.Flag, sources map[string]driver.Source, values map[string]string) (driver.Source, bool) {
+	source := sources[f.Name]
+	if source != driver.SourceEnv && source != driver.SourceConfig {
+		return "", false
+	}
+	if _, ok := f.Value.(interface {
+		Source() driver.Source
+	}); ok {
+		return source, driver.SourceOf(f.Value) == source
+	}
+	value, ok := values[f.Name]
+	return source, ok && value == f.Value.String()
+}
+
+// redactValue returns v for use in an error message about f, or a placeholder if f holds a secret.
+func redactValue(f *flag.Flag, v interface{}) interface{} {
+	if _, ok := f.Value.(driver.Sensitive); ok {
+		return typeflag.RedactedText
+	}
+	return v
+}
+
+func (r *Resolver) readConfig() (map[string]interface{}, error) {
+	if r.ConfigFile == "" {
+		return nil, nil
+	}
+	decoders := r.Decoders
+	if decoders == nil {
+		decoders = encoding.DefaultDecoders
+	}
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(r.ConfigFile)), ".")
+	if ext == "yml" {
+		ext = "yaml"
+	}
+	maker, ok := decoders[ext]
+	if !ok {
+		return nil, fmt.Errorf("flag: no decoder for config file %s", r.ConfigFile)
+	}
+	file, err := os.Open(r.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	decoder := maker.NewDecoder(file)
+	if d, ok := decoder.(interface {
+		UseNumber()
+	}); ok {
+		// Keep JSON numbers exact rather than rounding large integers through float64.
+		d.UseNumber()
+	}
+	config := map[string]interface{}{}
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("flag: failed to decode config file %s: %v", r.ConfigFile, err)
+	}
+	return config, nil
+}
+
+// lookupConfig finds name as a top-level key, or as a path of nested keys.
+func lookupConfig(config map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := config[name]; ok {
+		return v, true
+	}
+	if v, ok := lookupPath(config, strings.Split(name, ".")); ok {
+		return v, true
+	}
+	return lookupPath(config, strings.FieldsFunc(name, func(r rune) bool {
+		return r == '.' || r == '-'
+	}))
+}
+
+func lookupPath(config map[string]interface{}, path []string) (interface{}, bool) {
+	var current interface{} = config
+	var ok bool
+	for _, key := range path {
+		switch m := current.(type) {
+		case map[string]interface{}:
+			current, ok = m[key]
+		case map[interface{}]interface{}:
+			current, ok = m[key]
+		default:
+			return nil, false
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setFromConfig sets a flag from a decoded config value. Lists are set element by element
+// (or joined with the separator of CSV values), maps as KEY=VALUE pairs sorted by key,
+// and JSON flag values receive the value re-encoded as JSON.
+func setFromConfig(fs *flag.FlagSet, f *flag.Flag, v interface{}) error {
+	if driver.ValueType(f.Value) == "json" {
+		b, err := json.Marshal(normalize(v))
+		if err != nil {
+			return err
+		}
+		return fs.Set(f.Name, string(b))
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		texts := make([]string, len(v))
+		for i, e := range v {
+			texts[i] = configText(e)
+		}
+		if separator, ok := stringField(f.Value, "Separator"); ok {
+			if separator == "" {
+				separator = ","
+			}
+			return fs.Set(f.Name, strings.Join(texts, separator))
+		}
+		for _, text := range texts {
+			if err := fs.Set(f.Name, text); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}, map[interface{}]interface{}:
+		m := normalize(v).(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		separator, _ := stringField(f.Value, "Separator")
+		if separator == "" {
+			separator = "="
+		}
+		for _, k := range keys {
+			if err := fs.Set(f.Name, k+separator+configText(m[k])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fs.Set(f.Name, configText(v))
+}
+
+// configText formats a scalar config value as a flag argument. JSON numbers keep
+// their literal text, and floats are written without exponents so that integer flags accept them.
+func configText(v interface{}) string {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
+
+// stringField returns the named string field of the struct pointed to by v, if it has one.
+func stringField(v interface{}, name string) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return "", false
+	}
+	field := rv.Elem().FieldByName(name)
+	if !field.IsValid() || field.Kind() != reflect.String {
+		return "", false
+	}
+	return field.String(), true
+}
+
+// normalize converts the map[interface{}]interface{} values produced by YAML into map[string]interface{}.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normalize(e)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = normalize(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = normalize(e)
+		}
+		return l
+	}
+	return v
+}
@@ -0,0 +1,137 @@
+package flag_test
+
+import (
+	goflag "flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/gofunct/functional/flag"
+	"github.com/gofunct/functional/flag/driver"
+	"github.com/gofunct/functional/flag/timeflag"
+)
+
+func TestResolverConfigNumbers(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		flag   string
+		want   string
+	}{
+		{"large int", `{"size": 10000000}`, "size", "10000000"},
+		{"int beyond float precision", `{"size": 9007199254740993}`, "size", "9007199254740993"},
+		{"int", `{"size": 42}`, "size", "42"},
+		{"float", `{"ratio": 0.000001}`, "ratio", "1e-06"},
+		{"int list", `{"ports": [8080, 10000000]}`, "ports", "[8080 10000000]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+			fs.Int("size", 0, "")
+			fs.Float64("ratio", 0, "")
+			ports := &intList{}
+			fs.Var(ports, "ports", "")
+			if err := fs.Parse(nil); err != nil {
+				t.Fatal(err)
+			}
+			r := &flag.Resolver{ConfigFile: path}
+			if err := r.Resolve(fs); err != nil {
+				t.Fatal(err)
+			}
+			if got := fs.Lookup(tt.flag).Value.String(); got != tt.want {
+				t.Errorf("-%s = %s, want %s", tt.flag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverSecondResolve(t *testing.T) {
+	t.Setenv("APP_SIZE", "1")
+	t.Setenv("APP_TIMEOUT", "1s")
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"ratio": 0.5, "delay": "2s"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		set     map[string]string
+		sources map[string]driver.Source
+	}{
+		{
+			name:    "unchanged",
+			sources: map[string]driver.Source{"size": driver.SourceEnv, "ratio": driver.SourceConfig, "timeout": driver.SourceEnv, "delay": driver.SourceConfig},
+		},
+		{
+			name:    "set on the command line",
+			args:    []string{"-size", "2", "-delay", "2s"},
+			sources: map[string]driver.Source{"size": driver.SourceFlag, "ratio": driver.SourceConfig, "timeout": driver.SourceEnv, "delay": driver.SourceFlag},
+		},
+		{
+			name:    "changed after resolving",
+			set:     map[string]string{"ratio": "0.25", "timeout": "3s"},
+			sources: map[string]driver.Source{"size": driver.SourceEnv, "ratio": driver.SourceFlag, "timeout": driver.SourceFlag, "delay": driver.SourceConfig},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+			fs.Int("size", 0, "")
+			fs.Float64("ratio", 0, "")
+			driver.Var(fs, &timeflag.Duration{}, "timeout", "")
+			driver.Var(fs, &timeflag.Duration{}, "delay", "")
+			if err := fs.Parse(nil); err != nil {
+				t.Fatal(err)
+			}
+			r := &flag.Resolver{EnvPrefix: "APP", ConfigFile: path}
+			if err := r.Resolve(fs); err != nil {
+				t.Fatal(err)
+			}
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			for name, v := range tt.set {
+				if err := fs.Set(name, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := r.Resolve(fs); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, want := range tt.sources {
+				if got := r.Source(name); got != want {
+					t.Errorf("Source(%s) = %s, want %s", name, got, want)
+				}
+			}
+			for _, name := range []string{"timeout", "delay"} {
+				if got, want := driver.SourceOf(fs.Lookup(name).Value), tt.sources[name]; got != want {
+					t.Errorf("SourceOf(%s) = %s, want %s", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// intList is a list flag of integers.
+type intList []int
+
+func (l *intList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *intList) Set(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, n)
+	return nil
+}
@@ -0,0 +1,62 @@
+package timeflag
+
+import (
+	"github.com/robfig/cron/v3"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Cron is a `flag.Value` for cron schedule arguments such as `*/5 * * * *` or `@daily`.
+// By default, the standard five-field syntax is accepted.
+// If `Seconds` is set, an optional leading seconds field is accepted as well.
+type Cron struct {
+	driver.Meta
+
+	Seconds bool
+
+	Value cron.Schedule
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Cron) Help() string {
+	if fv.Seconds {
+		return "a cron schedule expression with optional seconds field"
+	}
+	return "a cron schedule expression"
+}
+
+// Set is flag.Value.Set
+func (fv *Cron) Set(v string) error {
+	options := cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor
+	if fv.Seconds {
+		options |= cron.SecondOptional
+	}
+	schedule, err := cron.NewParser(options).Parse(v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = schedule
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Cron) String() string {
+	return fv.Text
+}
+
+// ValueString returns the schedule expression.
+func (fv *Cron) ValueString() string {
+	return fv.Text
+}
+
+// ValueType returns the type name of the value.
+func (fv *Cron) ValueType() string {
+	return "cron"
+}
+
+// Type is pflag.Value.Type
+func (fv *Cron) Type() string {
+	return fv.ValueType()
+}
@@ -0,0 +1,33 @@
+package timeflag
+
+import (
+	"time"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+// Duration is a `flag.Value` for `time.Duration` arguments.
+type Duration = typeflag.Value[time.Duration, durationParser]
+
+// Durations is a `flag.Value` for `time.Duration` arguments.
+type Durations = typeflag.List[time.Duration, durationParser]
+
+// DurationsCSV is a `flag.Value` for `time.Duration` arguments.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type DurationsCSV = typeflag.CSV[time.Duration, durationParser]
+
+type durationParser struct{}
+
+func (durationParser) Parse(v string) (time.Duration, error) { return time.ParseDuration(v) }
+
+func (durationParser) Format(d time.Duration) string { return d.String() }
+
+func (durationParser) Describe(plural bool) string {
+	if plural {
+		return "durations"
+	}
+	return "a duration such as 300ms, 1.5h or 2h45m"
+}
+
+func (durationParser) TypeName() string { return "duration" }
@@ -1 +1,194 @@
 package timeflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// DefaultLayouts are the layouts tried, in order, when no `Layouts` are given.
+var DefaultLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Time is a `flag.Value` for `time.Time` arguments.
+// The value of the `Layouts` field is used for parsing when specified, otherwise `DefaultLayouts`.
+// Relative expressions such as `now`, `today`, `yesterday`, `tomorrow`, `-2h` or `+3d` are also accepted.
+// The `Location` field is used for layouts without a zone and for relative day expressions (default `time.Local`).
+// The `Now` field is used as the reference time for relative expressions when set.
+type Time struct {
+	driver.Meta
+
+	Layouts  []string
+	Location *time.Location
+	Now      func() time.Time
+
+	Value time.Time
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Time) Help() string {
+	return timeHelp(fv.Layouts)
+}
+
+// Set is flag.Value.Set
+func (fv *Time) Set(v string) error {
+	t, err := parseTime(v, fv.Layouts, fv.Location, fv.Now)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = t
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Time) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed time in RFC3339 format.
+func (fv *Time) ValueString() string {
+	if fv.Value.IsZero() {
+		return ""
+	}
+	return fv.Value.Format(time.RFC3339Nano)
+}
+
+// ValueType returns the type name of the value.
+func (fv *Time) ValueType() string {
+	return "time"
+}
+
+// Type is pflag.Value.Type
+func (fv *Time) Type() string {
+	return fv.ValueType()
+}
+
+// Times is a `flag.Value` for `time.Time` arguments.
+// The value of the `Layouts` field is used for parsing when specified, otherwise `DefaultLayouts`.
+// Relative expressions such as `now`, `today`, `yesterday`, `tomorrow`, `-2h` or `+3d` are also accepted.
+// The `Location` field is used for layouts without a zone and for relative day expressions (default `time.Local`).
+// The `Now` field is used as the reference time for relative expressions when set.
+type Times struct {
+	driver.Meta
+
+	Layouts  []string
+	Location *time.Location
+	Now      func() time.Time
+
+	Values []time.Time
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Times) Help() string {
+	return timeHelp(fv.Layouts)
+}
+
+// Set is flag.Value.Set
+func (fv *Times) Set(v string) error {
+	t, err := parseTime(v, fv.Layouts, fv.Location, fv.Now)
+	if err != nil {
+		return err
+	}
+	fv.Texts = append(fv.Texts, v)
+	fv.Values = append(fv.Values, t)
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Times) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed times in RFC3339 format.
+func (fv *Times) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, t := range fv.Values {
+		texts[i] = t.Format(time.RFC3339Nano)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Times) ValueType() string {
+	return "timeSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Times) Type() string {
+	return fv.ValueType()
+}
+
+func timeHelp(layouts []string) string {
+	if len(layouts) == 0 {
+		layouts = DefaultLayouts
+	}
+	return fmt.Sprintf("a time in one of the layouts %q, or relative (now, today, yesterday, tomorrow, -2h, +3d)", layouts)
+}
+
+// parseTime parses v as a relative expression, or using the first matching layout.
+func parseTime(v string, layouts []string, loc *time.Location, now func() time.Time) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	ref := time.Now()
+	if now != nil {
+		ref = now()
+	}
+	ref = ref.In(loc)
+	midnight := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, loc)
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "now":
+		return ref, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	}
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		d, err := parseRelative(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return ref.Add(d), nil
+	}
+	if len(layouts) == 0 {
+		layouts = DefaultLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`"%s" must be a time in one of the layouts %q, or a relative expression`, v, layouts)
+}
+
+// parseRelative parses a signed duration, additionally accepting the units `d` (days) and `w` (weeks).
+func parseRelative(v string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(v, suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(v, suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf(`not a valid relative time: "%s"`, v)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf(`not a valid relative time: "%s"`, v)
+	}
+	return d, nil
+}
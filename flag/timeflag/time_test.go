@@ -0,0 +1,185 @@
+package timeflag_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofunct/functional/flag/timeflag"
+)
+
+var (
+	zone = time.FixedZone("UTC+2", 2*60*60)
+	now  = time.Date(2024, 3, 10, 15, 30, 0, 0, zone)
+)
+
+func fixedNow() time.Time { return now }
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		arg     string
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{arg: "now", want: now},
+		{arg: " NOW ", want: now},
+		{arg: "today", want: time.Date(2024, 3, 10, 0, 0, 0, 0, zone)},
+		{arg: "yesterday", want: time.Date(2024, 3, 9, 0, 0, 0, 0, zone)},
+		{arg: "tomorrow", want: time.Date(2024, 3, 11, 0, 0, 0, 0, zone)},
+		{arg: "-2h", want: now.Add(-2 * time.Hour)},
+		{arg: "+90m", want: now.Add(90 * time.Minute)},
+		{arg: "+3d", want: now.Add(72 * time.Hour)},
+		{arg: "-1.5d", want: now.Add(-36 * time.Hour)},
+		{arg: "-1w", want: now.Add(-7 * 24 * time.Hour)},
+		{arg: "-xd", wantErr: true},
+		{arg: "+2x", wantErr: true},
+		{arg: "2024-01-02T03:04:05.5Z", want: time.Date(2024, 1, 2, 3, 4, 5, 5e8, time.UTC)},
+		{arg: "2024-01-02T03:04:05+01:00", want: time.Date(2024, 1, 2, 2, 4, 5, 0, time.UTC)},
+		{arg: "2024-01-02T03:04:05", want: time.Date(2024, 1, 2, 3, 4, 5, 0, zone)},
+		{arg: "2024-01-02 03:04:05", want: time.Date(2024, 1, 2, 3, 4, 5, 0, zone)},
+		{arg: "2024-01-02", want: time.Date(2024, 1, 2, 0, 0, 0, 0, zone)},
+		{arg: "02/01/2024", layouts: []string{"02/01/2006"}, want: time.Date(2024, 1, 2, 0, 0, 0, 0, zone)},
+		{arg: "2024-01-02", layouts: []string{"02/01/2006"}, wantErr: true},
+		{arg: "2024-13-02", wantErr: true},
+		{arg: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &timeflag.Time{Layouts: tt.layouts, Location: zone, Now: fixedNow}
+			err := fv.Set(tt.arg)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Set(%q) = %v, want an error", tt.arg, fv.Value)
+				}
+				if fv.HasChanged() {
+					t.Error("failed Set marked the value changed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !fv.Value.Equal(tt.want) {
+				t.Errorf("Set(%q) = %v, want %v", tt.arg, fv.Value, tt.want)
+			}
+			if fv.String() != tt.arg || !fv.HasChanged() {
+				t.Errorf("String = %q, HasChanged = %v", fv.String(), fv.HasChanged())
+			}
+		})
+	}
+}
+
+func TestTimes(t *testing.T) {
+	fv := &timeflag.Times{Location: zone, Now: fixedNow}
+	for _, arg := range []string{"today", "2024-01-02"} {
+		if err := fv.Set(arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := fv.ValueString(), "2024-03-10T00:00:00+02:00,2024-01-02T00:00:00+02:00"; got != want {
+		t.Errorf("ValueString = %q, want %q", got, want)
+	}
+	if got := fv.String(); got != "today,2024-01-02" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, zone) }
+	tests := []struct {
+		arg       string
+		separator string
+		start     time.Time
+		end       time.Time
+		wantErr   bool
+	}{
+		{arg: "2024-03-01..2024-03-05", start: day(1), end: day(5)},
+		{arg: "2024-03-01 .. today", start: day(1), end: day(10)},
+		{arg: "-1d..", start: now.Add(-24 * time.Hour)},
+		{arg: "..2024-03-05", end: day(5)},
+		{arg: "..", start: time.Time{}, end: time.Time{}},
+		{arg: "2024-03-01/2024-03-05", separator: "/", start: day(1), end: day(5)},
+		{arg: "2024-03-05..2024-03-01", wantErr: true},
+		{arg: "2024-03-01", wantErr: true},
+		{arg: "2024-03-01..later", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &timeflag.TimeRange{Separator: tt.separator, Location: zone, Now: fixedNow}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if !fv.Value.Start.Equal(tt.start) || !fv.Value.End.Equal(tt.end) {
+				t.Errorf("Set(%q) = %v..%v, want %v..%v", tt.arg, fv.Value.Start, fv.Value.End, tt.start, tt.end)
+			}
+		})
+	}
+
+	fv := &timeflag.TimeRange{Location: zone}
+	if err := fv.Set("2024-03-01..2024-03-05"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		t    time.Time
+		want bool
+	}{{day(1), true}, {day(3), true}, {day(5), true}, {day(6), false}, {day(1).Add(-time.Second), false}} {
+		if got := fv.Contains(tt.t); got != tt.want {
+			t.Errorf("Contains(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestDurationsCSV(t *testing.T) {
+	fv := &timeflag.DurationsCSV{}
+	if err := fv.Set("1s, 1m30s"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fv.Set("2h"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fv.ValueString(); got != "2h0m0s" {
+		t.Errorf("ValueString = %q, want 2h0m0s", got)
+	}
+	if err := fv.Set("1s,soon"); err == nil {
+		t.Error("Set accepted an invalid duration")
+	}
+	if got := fv.ValueString(); got != "2h0m0s" {
+		t.Errorf("ValueString after error = %q, want 2h0m0s", got)
+	}
+}
+
+func TestCron(t *testing.T) {
+	from := time.Date(2024, 3, 10, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		arg     string
+		seconds bool
+		next    time.Time
+		wantErr bool
+	}{
+		{arg: "*/20 * * * *", next: time.Date(2024, 3, 10, 15, 40, 0, 0, time.UTC)},
+		{arg: "@daily", next: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)},
+		{arg: "15 * * * * *", seconds: true, next: time.Date(2024, 3, 10, 15, 30, 15, 0, time.UTC)},
+		{arg: "0 16 * * *", seconds: true, next: time.Date(2024, 3, 10, 16, 0, 0, 0, time.UTC)},
+		{arg: "15 * * * * *", wantErr: true},
+		{arg: "61 * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &timeflag.Cron{Seconds: tt.seconds}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if next := fv.Value.Next(from); !next.Equal(tt.next) {
+				t.Errorf("Next = %v, want %v", next, tt.next)
+			}
+		})
+	}
+}
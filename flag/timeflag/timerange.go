@@ -0,0 +1,109 @@
+package timeflag
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// TimeRange is a `flag.Value` for `START..END` time range arguments.
+// Either bound may be omitted (`START..`, `..END`), leaving it as the zero `time.Time`.
+// Each bound is parsed like a `Time`, using the `Layouts`, `Location` and `Now` fields.
+// The value of the `Separator` field is used instead of `".."` when set.
+type TimeRange struct {
+	driver.Meta
+
+	Separator string
+	Layouts   []string
+	Location  *time.Location
+	Now       func() time.Time
+
+	Value struct {
+		Start time.Time
+		End   time.Time
+	}
+	Text string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *TimeRange) Help() string {
+	separator := ".."
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("a time range START%sEND, each bound optional and %s", separator, timeHelp(fv.Layouts))
+}
+
+// Set is flag.Value.Set
+func (fv *TimeRange) Set(v string) error {
+	separator := ".."
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	i := strings.Index(v, separator)
+	if i < 0 {
+		return fmt.Errorf(`"%s" must have the form START%sEND`, v, separator)
+	}
+	var start, end time.Time
+	var err error
+	if s := strings.TrimSpace(v[:i]); s != "" {
+		if start, err = parseTime(s, fv.Layouts, fv.Location, fv.Now); err != nil {
+			return err
+		}
+	}
+	if s := strings.TrimSpace(v[i+len(separator):]); s != "" {
+		if end, err = parseTime(s, fv.Layouts, fv.Location, fv.Now); err != nil {
+			return err
+		}
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return fmt.Errorf(`"%s": end of range is before its start`, v)
+	}
+	fv.Text = v
+	fv.Value.Start = start
+	fv.Value.End = end
+	fv.MarkChanged()
+	return nil
+}
+
+// Contains reports whether t lies within the range. Omitted bounds are unbounded.
+func (fv *TimeRange) Contains(t time.Time) bool {
+	if !fv.Value.Start.IsZero() && t.Before(fv.Value.Start) {
+		return false
+	}
+	if !fv.Value.End.IsZero() && t.After(fv.Value.End) {
+		return false
+	}
+	return true
+}
+
+func (fv *TimeRange) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed bounds in RFC3339 format.
+func (fv *TimeRange) ValueString() string {
+	if fv.Value.Start.IsZero() && fv.Value.End.IsZero() {
+		return ""
+	}
+	var start, end string
+	if !fv.Value.Start.IsZero() {
+		start = fv.Value.Start.Format(time.RFC3339Nano)
+	}
+	if !fv.Value.End.IsZero() {
+		end = fv.Value.End.Format(time.RFC3339Nano)
+	}
+	return start + ".." + end
+}
+
+// ValueType returns the type name of the value.
+func (fv *TimeRange) ValueType() string {
+	return "timeRange"
+}
+
+// Type is pflag.Value.Type
+func (fv *TimeRange) Type() string {
+	return fv.ValueType()
+}
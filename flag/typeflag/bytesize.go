@@ -0,0 +1,110 @@
+package typeflag
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// byteSizeUnits maps lower-case unit suffixes to their size in bytes.
+// SI units (KB, MB, ...) are powers of 1000, IEC units (KiB, MiB, ...) powers of 1024.
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1e6,
+	"mb":  1e6,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1e9,
+	"gb":  1e9,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1e12,
+	"tb":  1e12,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"p":   1e15,
+	"pb":  1e15,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+	"e":   1e18,
+	"eb":  1e18,
+	"ei":  1 << 60,
+	"eib": 1 << 60,
+}
+
+// ByteSize is a `flag.Value` for byte size arguments such as `512`, `10MiB` or `1.5GB`.
+// SI units (KB, MB, ...) are powers of 1000, IEC units (KiB, MiB, ...) powers of 1024.
+type ByteSize struct {
+	driver.Meta
+
+	Value uint64
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *ByteSize) Help() string {
+	return "a byte size such as 512, 10MiB or 1.5GB"
+}
+
+// Set is flag.Value.Set
+func (fv *ByteSize) Set(v string) error {
+	n, err := parseByteSize(v)
+	if err != nil {
+		return err
+	}
+	fv.Value = n
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *ByteSize) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed size in bytes.
+func (fv *ByteSize) ValueString() string {
+	return strconv.FormatUint(fv.Value, 10)
+}
+
+// ValueType returns the type name of the value.
+func (fv *ByteSize) ValueType() string {
+	return "bytesize"
+}
+
+// Type is pflag.Value.Type
+func (fv *ByteSize) Type() string {
+	return fv.ValueType()
+}
+
+func parseByteSize(v string) (uint64, error) {
+	s := strings.TrimSpace(v)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+	unit, ok := byteSizeUnits[strings.ToLower(strings.TrimSpace(s[i:]))]
+	if !ok {
+		return 0, fmt.Errorf(`"%s" has an unknown byte size unit`, v)
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(s[:i]), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf(`not a valid byte size: "%s"`, v)
+	}
+	size := n * unit
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf(`byte size out of range: "%s"`, v)
+	}
+	return uint64(size), nil
+}
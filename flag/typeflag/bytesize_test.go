@@ -0,0 +1,55 @@
+package typeflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestByteSize(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    uint64
+		wantErr bool
+	}{
+		{arg: "0", want: 0},
+		{arg: "512", want: 512},
+		{arg: "512b", want: 512},
+		{arg: "1k", want: 1000},
+		{arg: "1KB", want: 1000},
+		{arg: "1KiB", want: 1024},
+		{arg: "1ki", want: 1024},
+		{arg: "1.5GB", want: 1500000000},
+		{arg: "1.5 GiB", want: 3 << 29},
+		{arg: " 10MiB ", want: 10 << 20},
+		{arg: "2TB", want: 2e12},
+		{arg: "1PiB", want: 1 << 50},
+		{arg: "15EiB", want: 15 << 60},
+		{arg: "16EiB", wantErr: true},
+		{arg: "-1", wantErr: true},
+		{arg: "1XB", wantErr: true},
+		{arg: "MB", wantErr: true},
+		{arg: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &typeflag.ByteSize{}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				if fv.HasChanged() {
+					t.Error("failed Set marked the value changed")
+				}
+				return
+			}
+			if fv.Value != tt.want {
+				t.Errorf("Set(%q) = %d, want %d", tt.arg, fv.Value, tt.want)
+			}
+			if fv.String() != tt.arg {
+				t.Errorf("String = %q, want %q", fv.String(), tt.arg)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package typeflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Float is a `flag.Value` for `float` arguments.
+// The `BitSize` field is used for parsing when set.
+type Float struct {
+	driver.Meta
+
+	BitSize int
+
+	Value float64
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Float) Help() string {
+	if fv.BitSize != 0 {
+		return fmt.Sprintf("a %d-bit float", fv.BitSize)
+	}
+	return "a float"
+}
+
+// Set is flag.Value.Set
+func (fv *Float) Set(v string) error {
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	n, err := strconv.ParseFloat(v, bitSize)
+	if err == nil {
+		fv.Value = n
+		fv.Text = v
+		fv.MarkChanged()
+	}
+	return err
+}
+
+func (fv *Float) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed float.
+func (fv *Float) ValueString() string {
+	return strconv.FormatFloat(fv.Value, 'g', -1, 64)
+}
+
+// ValueType returns the type name of the value.
+func (fv *Float) ValueType() string {
+	return "float64"
+}
+
+// Type is pflag.Value.Type
+func (fv *Float) Type() string {
+	return fv.ValueType()
+}
+
+// Floats is a `flag.Value` for `float` arguments.
+// The `BitSize` field is used for parsing when set.
+type Floats struct {
+	driver.Meta
+
+	BitSize int
+
+	Values []float64
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Floats) Help() string {
+	if fv.BitSize != 0 {
+		return fmt.Sprintf("a %d-bit float", fv.BitSize)
+	}
+	return "a float"
+}
+
+// Set is flag.Value.Set
+func (fv *Floats) Set(v string) error {
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	n, err := strconv.ParseFloat(v, bitSize)
+	if err == nil {
+		fv.Values = append(fv.Values, n)
+		fv.Texts = append(fv.Texts, v)
+		fv.MarkChanged()
+	}
+	return err
+}
+
+func (fv *Floats) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed floats.
+func (fv *Floats) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Floats) ValueType() string {
+	return "float64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Floats) Type() string {
+	return fv.ValueType()
+}
+
+// FloatsCSV is a `flag.Value` for comma-separated `float` arguments.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `BitSize` field is used for parsing when set.
+// The `Separator` field is used instead of the comma when set.
+type FloatsCSV struct {
+	driver.Meta
+
+	BitSize    int
+	Separator  string
+	Accumulate bool
+
+	Values []float64
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *FloatsCSV) Help() string {
+	var bitSize string
+	if fv.BitSize != 0 {
+		bitSize = fmt.Sprintf("%d-bit ", fv.BitSize)
+	}
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of %sfloats", separator, bitSize)
+}
+
+// Set is flag.Value.Set
+func (fv *FloatsCSV) Set(v string) error {
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		n, err := strconv.ParseFloat(part, bitSize)
+		if err != nil {
+			return err
+		}
+		fv.Values = append(fv.Values, n)
+		fv.Texts = append(fv.Texts, part)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *FloatsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed floats.
+func (fv *FloatsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *FloatsCSV) ValueType() string {
+	return "float64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *FloatsCSV) Type() string {
+	return fv.ValueType()
+}
@@ -0,0 +1,349 @@
+package typeflag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Parser parses and prints the values of type T for `Value`, `List` and `CSV`.
+// Its zero value must be ready to use; fields of the parser type may configure the parsing.
+// Flag values with fields of their own can share the parsing through `Append`, `AppendCSV` and `CSVHelp`.
+type Parser[T any] interface {
+	// Parse parses one value.
+	Parse(v string) (T, error)
+	// Format prints a parsed value.
+	Format(value T) string
+	// Describe describes one value, such as "an IP address", or several, such as "IP addresses",
+	// for help messages.
+	Describe(plural bool) string
+	// TypeName returns the type name of single values, such as "ip"; lists append "Slice".
+	TypeName() string
+}
+
+// Funcs is a `Parser` built from functions, for types that need no parser of their own.
+// `FormatFunc` defaults to fmt.Sprint, `Description` to "a value", `Plural` to "values"
+// and `Name` to "value".
+//
+// Example:
+//
+//  type versionParser = typeflag.Funcs[*semver.Version]
+//  ...
+//  version := &typeflag.Value[*semver.Version, versionParser]{
+//  	Parser: versionParser{ParseFunc: semver.NewVersion, Description: "a semantic version"},
+//  }
+//  fs.Var(version, "version", "")
+type Funcs[T any] struct {
+	ParseFunc   func(string) (T, error)
+	FormatFunc  func(T) string
+	Description string
+	Plural      string
+	Name        string
+}
+
+// Parse is Parser.Parse
+func (p Funcs[T]) Parse(v string) (T, error) {
+	if p.ParseFunc == nil {
+		var zero T
+		return zero, errNoParser
+	}
+	return p.ParseFunc(v)
+}
+
+// Format is Parser.Format
+func (p Funcs[T]) Format(value T) string {
+	if p.FormatFunc == nil {
+		return fmt.Sprint(value)
+	}
+	return p.FormatFunc(value)
+}
+
+// Describe is Parser.Describe; `Plural` defaults to "values".
+func (p Funcs[T]) Describe(plural bool) string {
+	switch {
+	case plural && p.Plural != "":
+		return p.Plural
+	case plural:
+		return "values"
+	case p.Description != "":
+		return p.Description
+	}
+	return "a value"
+}
+
+// TypeName is Parser.TypeName
+func (p Funcs[T]) TypeName() string {
+	if p.Name == "" {
+		return "value"
+	}
+	return p.Name
+}
+
+// Value is a `flag.Value` for arguments of any type, parsed by the `Parser`.
+//
+// Example:
+//
+//  // IP is a `flag.Value` for IP addresses.
+//  type IP = typeflag.Value[net.IP, ipParser]
+type Value[T any, P Parser[T]] struct {
+	driver.Meta
+
+	Parser P
+
+	Value T
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Value[T, P]) Help() string {
+	return fv.Parser.Describe(false)
+}
+
+// Set is flag.Value.Set
+func (fv *Value[T, P]) Set(v string) error {
+	value, err := fv.Parser.Parse(v)
+	if err != nil {
+		return err
+	}
+	fv.Text = v
+	fv.Value = value
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Value[T, P]) String() string {
+	if fv.Text == "" && !isZero(fv.Value) {
+		return fv.Parser.Format(fv.Value)
+	}
+	return fv.Text
+}
+
+// ValueString returns the parsed value printed by the `Parser`.
+func (fv *Value[T, P]) ValueString() string {
+	if isZero(fv.Value) {
+		return ""
+	}
+	return fv.Parser.Format(fv.Value)
+}
+
+// ValueType returns the type name of the value.
+func (fv *Value[T, P]) ValueType() string {
+	return fv.Parser.TypeName()
+}
+
+// Type is pflag.Value.Type
+func (fv *Value[T, P]) Type() string {
+	return fv.ValueType()
+}
+
+// List is a `flag.Value` for repeated arguments of any type, parsed by the `Parser`.
+// If `Unique` is set, values whose formatted form was seen before are dropped.
+// If `Less` is set, the values are kept sorted by it.
+type List[T any, P Parser[T]] struct {
+	driver.Meta
+
+	Parser P
+	Unique bool
+	Less   func(a, b T) bool
+
+	Values []T
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *List[T, P]) Help() string {
+	return fv.Parser.Describe(false)
+}
+
+// Set is flag.Value.Set
+func (fv *List[T, P]) Set(v string) error {
+	values, texts, err := Append[T](fv.Parser, fv.Values, fv.Texts, v, fv.Unique, fv.Less)
+	if err != nil {
+		return err
+	}
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *List[T, P]) String() string {
+	return listString(fv.Values, fv.Texts, fv.Parser.Format)
+}
+
+// ValueString returns the parsed values printed by the `Parser`.
+func (fv *List[T, P]) ValueString() string {
+	return FormatValues[T](fv.Parser, fv.Values)
+}
+
+// ValueType returns the type name of the value.
+func (fv *List[T, P]) ValueType() string {
+	return fv.Parser.TypeName() + "Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *List[T, P]) Type() string {
+	return fv.ValueType()
+}
+
+// CSV is a `flag.Value` for comma-separated arguments of any type, parsed element-wise by the `Parser`.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+// If `Unique` is set, values whose formatted form was seen before are dropped.
+// If `Less` is set, the values are kept sorted by it.
+type CSV[T any, P Parser[T]] struct {
+	driver.Meta
+
+	Parser     P
+	Unique     bool
+	Less       func(a, b T) bool
+	Separator  string
+	Accumulate bool
+
+	Values []T
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *CSV[T, P]) Help() string {
+	return CSVHelp[T](fv.Parser, fv.Separator)
+}
+
+// Set is flag.Value.Set
+func (fv *CSV[T, P]) Set(v string) error {
+	values, texts := fv.Values, fv.Texts
+	if !fv.Accumulate {
+		values, texts = nil, nil
+	}
+	values, texts, err := AppendCSV[T](fv.Parser, values, texts, v, fv.Separator, fv.Unique, fv.Less)
+	if err != nil {
+		return err
+	}
+	fv.Values, fv.Texts = values, texts
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *CSV[T, P]) String() string {
+	return listString(fv.Values, fv.Texts, fv.Parser.Format)
+}
+
+// ValueString returns the parsed values printed by the `Parser`.
+func (fv *CSV[T, P]) ValueString() string {
+	return FormatValues[T](fv.Parser, fv.Values)
+}
+
+// ValueType returns the type name of the value.
+func (fv *CSV[T, P]) ValueType() string {
+	return fv.Parser.TypeName() + "Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *CSV[T, P]) Type() string {
+	return fv.ValueType()
+}
+
+// Append parses v with p and appends the value and v to values and texts, as `List.Set` does,
+// for list values with fields of their own. If unique is set, a value whose formatted form
+// is already in values is dropped. If less is set, values and texts are kept sorted by it.
+func Append[T any](p Parser[T], values []T, texts []string, v string, unique bool, less func(a, b T) bool) ([]T, []string, error) {
+	value, err := p.Parse(v)
+	if err != nil {
+		return values, texts, err
+	}
+	values, texts = appendValue(values, texts, value, v, p.Format, unique)
+	sortValues(values, texts, less)
+	return values, texts, nil
+}
+
+// AppendCSV appends the values of the separator-separated list v, as `CSV.Set` does.
+// The comma is used if separator is empty. If an element of v fails to parse,
+// values and texts are returned unchanged.
+func AppendCSV[T any](p Parser[T], values []T, texts []string, v, separator string, unique bool, less func(a, b T) bool) ([]T, []string, error) {
+	if separator == "" {
+		separator = ","
+	}
+	newValues, newTexts := values, texts
+	for _, part := range strings.Split(v, separator) {
+		part = strings.TrimSpace(part)
+		value, err := p.Parse(part)
+		if err != nil {
+			return values, texts, err
+		}
+		newValues, newTexts = appendValue(newValues, newTexts, value, part, p.Format, unique)
+	}
+	sortValues(newValues, newTexts, less)
+	return newValues, newTexts, nil
+}
+
+// CSVHelp returns the help message of a separator-separated list of values parsed by p.
+func CSVHelp[T any](p Parser[T], separator string) string {
+	if separator == "" {
+		separator = ","
+	}
+	return fmt.Sprintf("%q-separated list of %s", separator, p.Describe(true))
+}
+
+// FormatValues returns values printed by p, separated by commas.
+func FormatValues[T any](p Parser[T], values []T) string {
+	return formatValues(values, p.Format)
+}
+
+var errNoParser = errors.New("typeflag.Funcs has no ParseFunc")
+
+func formatValues[T any](values []T, format func(T) string) string {
+	texts := make([]string, len(values))
+	for i, v := range values {
+		texts[i] = format(v)
+	}
+	return strings.Join(texts, ",")
+}
+
+// listString returns the texts the values were parsed from, or the formatted values if they were set directly.
+func listString[T any](values []T, texts []string, format func(T) string) string {
+	if len(texts) == 0 && len(values) > 0 {
+		return formatValues(values, format)
+	}
+	return strings.Join(texts, ",")
+}
+
+func appendValue[T any](values []T, texts []string, value T, text string, format func(T) string, unique bool) ([]T, []string) {
+	if unique {
+		formatted := format(value)
+		for _, v := range values {
+			if format(v) == formatted {
+				return values, texts
+			}
+		}
+	}
+	return append(values, value), append(texts, text)
+}
+
+// sortValues sorts values by less, keeping texts in the same order.
+func sortValues[T any](values []T, texts []string, less func(a, b T) bool) {
+	if less == nil || len(values) != len(texts) {
+		return
+	}
+	sort.Stable(byLess[T]{values: values, texts: texts, less: less})
+}
+
+type byLess[T any] struct {
+	values []T
+	texts  []string
+	less   func(a, b T) bool
+}
+
+func (s byLess[T]) Len() int           { return len(s.values) }
+func (s byLess[T]) Less(i, j int) bool { return s.less(s.values[i], s.values[j]) }
+func (s byLess[T]) Swap(i, j int) {
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+	s.texts[i], s.texts[j] = s.texts[j], s.texts[i]
+}
+
+func isZero[T any](v T) bool {
+	rv := reflect.ValueOf(&v).Elem()
+	return rv.IsZero()
+}
@@ -0,0 +1,160 @@
+package typeflag_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+type intParser = typeflag.Funcs[int]
+
+var ints = intParser{ParseFunc: strconv.Atoi, Description: "an integer", Plural: "integers", Name: "int"}
+
+func TestValue(t *testing.T) {
+	fv := &typeflag.Value[int, intParser]{Parser: ints}
+	if fv.String() != "" || fv.ValueString() != "" {
+		t.Errorf("zero value prints %q, %q", fv.String(), fv.ValueString())
+	}
+	if err := fv.Set("x"); err == nil || fv.HasChanged() {
+		t.Fatalf("Set(x) = %v, changed %v", err, fv.HasChanged())
+	}
+	if err := fv.Set("042"); err != nil {
+		t.Fatal(err)
+	}
+	if fv.Value != 42 || fv.Text != "042" || fv.String() != "042" || fv.ValueString() != "42" || !fv.HasChanged() {
+		t.Errorf("after Set(042): %+v, String %q, ValueString %q", fv, fv.String(), fv.ValueString())
+	}
+	if fv.Help() != "an integer" || fv.Type() != "int" {
+		t.Errorf("Help %q, Type %q", fv.Help(), fv.Type())
+	}
+
+	preset := &typeflag.Value[int, intParser]{Parser: ints, Value: 7}
+	if preset.String() != "7" {
+		t.Errorf("String of a preset value = %q, want 7", preset.String())
+	}
+}
+
+func TestList(t *testing.T) {
+	tests := []struct {
+		name       string
+		unique     bool
+		less       func(a, b int) bool
+		args       []string
+		wantValues []int
+		wantTexts  []string
+	}{
+		{"appends", false, nil, []string{"3", "1", "3"}, []int{3, 1, 3}, []string{"3", "1", "3"}},
+		{"unique", true, nil, []string{"3", "1", "03"}, []int{3, 1}, []string{"3", "1"}},
+		{"sorted", false, func(a, b int) bool { return a < b }, []string{"30", "1", "02"}, []int{1, 2, 30}, []string{"1", "02", "30"}},
+		{"unique and sorted", true, func(a, b int) bool { return a > b }, []string{"1", "3", "2", "3"}, []int{3, 2, 1}, []string{"3", "2", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := &typeflag.List[int, intParser]{Parser: ints, Unique: tt.unique, Less: tt.less}
+			for _, arg := range tt.args {
+				if err := fv.Set(arg); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(fv.Values, tt.wantValues) || !reflect.DeepEqual(fv.Texts, tt.wantTexts) {
+				t.Errorf("Values %v, Texts %q; want %v, %q", fv.Values, fv.Texts, tt.wantValues, tt.wantTexts)
+			}
+			if fv.Type() != "intSlice" || fv.Help() != "an integer" {
+				t.Errorf("Type %q, Help %q", fv.Type(), fv.Help())
+			}
+		})
+	}
+}
+
+func TestCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		fv         typeflag.CSV[int, intParser]
+		args       []string
+		wantValues []int
+		wantTexts  []string
+		wantHelp   string
+		wantErr    bool
+	}{
+		{
+			name:       "replaces",
+			args:       []string{"1,2", "3, 4"},
+			wantValues: []int{3, 4},
+			wantTexts:  []string{"3", "4"},
+			wantHelp:   `","-separated list of integers`,
+		},
+		{
+			name:       "accumulates",
+			fv:         typeflag.CSV[int, intParser]{Accumulate: true},
+			args:       []string{"1,2", "3"},
+			wantValues: []int{1, 2, 3},
+			wantTexts:  []string{"1", "2", "3"},
+			wantHelp:   `","-separated list of integers`,
+		},
+		{
+			name:       "separator",
+			fv:         typeflag.CSV[int, intParser]{Separator: ";"},
+			args:       []string{"1;2"},
+			wantValues: []int{1, 2},
+			wantTexts:  []string{"1", "2"},
+			wantHelp:   `";"-separated list of integers`,
+		},
+		{
+			name:       "unique and sorted",
+			fv:         typeflag.CSV[int, intParser]{Unique: true, Less: func(a, b int) bool { return a < b }, Accumulate: true},
+			args:       []string{"3,1", "02,3"},
+			wantValues: []int{1, 2, 3},
+			wantTexts:  []string{"1", "02", "3"},
+			wantHelp:   `","-separated list of integers`,
+		},
+		{
+			name:       "error keeps values",
+			fv:         typeflag.CSV[int, intParser]{Accumulate: true},
+			args:       []string{"1", "2,x"},
+			wantValues: []int{1},
+			wantTexts:  []string{"1"},
+			wantHelp:   `","-separated list of integers`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fv := &tt.fv
+			fv.Parser = ints
+			var err error
+			for _, arg := range tt.args {
+				if err = fv.Set(arg); err != nil {
+					break
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set() = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(fv.Values, tt.wantValues) || !reflect.DeepEqual(fv.Texts, tt.wantTexts) {
+				t.Errorf("Values %v, Texts %q; want %v, %q", fv.Values, fv.Texts, tt.wantValues, tt.wantTexts)
+			}
+			if fv.Help() != tt.wantHelp {
+				t.Errorf("Help = %q, want %q", fv.Help(), tt.wantHelp)
+			}
+		})
+	}
+}
+
+func TestFuncsDefaults(t *testing.T) {
+	var p typeflag.Funcs[int]
+	if _, err := p.Parse("1"); err == nil {
+		t.Error("Parse without ParseFunc succeeded")
+	}
+	if got := p.Format(12); got != "12" {
+		t.Errorf("Format = %q, want 12", got)
+	}
+	if p.Describe(false) != "a value" || p.Describe(true) != "values" || p.TypeName() != "value" {
+		t.Errorf("defaults: %q, %q, %q", p.Describe(false), p.Describe(true), p.TypeName())
+	}
+	p.FormatFunc = func(n int) string { return "#" + strconv.Itoa(n) }
+	if got := p.Format(12); got != "#12" {
+		t.Errorf("Format with FormatFunc = %q, want #12", got)
+	}
+}
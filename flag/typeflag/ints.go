@@ -1,15 +1,18 @@
 package typeflag
 
-
 import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Ints is a `flag.Value` for `int` arguments.
 // The `Base` and `BitSize` fields are used for parsing when set.
 type Ints struct {
+	driver.Meta
+
 	Base    int
 	BitSize int
 
@@ -46,6 +49,7 @@ func (fv *Ints) Set(v string) error {
 	if err == nil {
 		fv.Values = append(fv.Values, n)
 		fv.Texts = append(fv.Texts, v)
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -54,11 +58,32 @@ func (fv *Ints) String() string {
 	return strings.Join(fv.Texts, ",")
 }
 
+// ValueString returns the parsed integers in base 10.
+func (fv *Ints) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatInt(n, 10)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Ints) ValueType() string {
+	return "int64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Ints) Type() string {
+	return fv.ValueType()
+}
+
 // IntsCSV is a `flag.Value` for comma-separated `int` arguments.
 // If `Accumulate` is set, the values of all instances of the flag are accumulated.
 // The `Base` and `BitSize` fields are used for parsing when set.
 // The `Separator` field is used instead of the comma when set.
 type IntsCSV struct {
+	driver.Meta
+
 	Base       int
 	BitSize    int
 	Separator  string
@@ -112,9 +137,29 @@ func (fv *IntsCSV) Set(v string) error {
 		fv.Values = append(fv.Values, n)
 		fv.Texts = append(fv.Texts, part)
 	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *IntsCSV) String() string {
 	return strings.Join(fv.Texts, ",")
-}
\ No newline at end of file
+}
+
+// ValueString returns the parsed integers in base 10.
+func (fv *IntsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatInt(n, 10)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *IntsCSV) ValueType() string {
+	return "int64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *IntsCSV) Type() string {
+	return fv.ValueType()
+}
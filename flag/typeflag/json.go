@@ -3,10 +3,14 @@ package typeflag
 import (
 	"encoding/json"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // JSON is a `flag.Value` for JSON arguments.
 type JSON struct {
+	driver.Meta
+
 	Value interface{}
 	Text  string
 }
@@ -19,18 +23,45 @@ func (fv *JSON) Help() string {
 // Set is flag.Value.Set
 func (fv *JSON) Set(v string) error {
 	fv.Text = v
+	var err error
 	if fv.Value == nil {
-		return json.Unmarshal([]byte(v), &fv.Value)
+		err = json.Unmarshal([]byte(v), &fv.Value)
+	} else {
+		err = json.Unmarshal([]byte(v), fv.Value)
 	}
-	return json.Unmarshal([]byte(v), fv.Value)
+	if err == nil {
+		fv.MarkChanged()
+	}
+	return err
 }
 
 func (fv *JSON) String() string {
 	return fv.Text
 }
 
+// ValueString returns the parsed value re-encoded as JSON.
+func (fv *JSON) ValueString() string {
+	b, err := json.Marshal(fv.Value)
+	if err != nil {
+		return fv.Text
+	}
+	return string(b)
+}
+
+// ValueType returns the type name of the value.
+func (fv *JSON) ValueType() string {
+	return "json"
+}
+
+// Type is pflag.Value.Type
+func (fv *JSON) Type() string {
+	return fv.ValueType()
+}
+
 // JSONs is a `flag.Value` for JSON arguments. If non-nil, the `Value` field is used to generate template values.
 type JSONs struct {
+	driver.Meta
+
 	Value  func() interface{}
 	Values []interface{}
 	Texts  []string
@@ -53,6 +84,7 @@ func (fv *JSONs) Set(v string) (err error) {
 	if err == nil {
 		fv.Texts = append(fv.Texts, v)
 		fv.Values = append(fv.Values, value)
+		fv.MarkChanged()
 	}
 	return err
 }
@@ -60,3 +92,22 @@ func (fv *JSONs) Set(v string) (err error) {
 func (fv *JSONs) String() string {
 	return strings.Join(fv.Texts, ",")
 }
+
+// ValueString returns the parsed values re-encoded as a JSON array.
+func (fv *JSONs) ValueString() string {
+	b, err := json.Marshal(fv.Values)
+	if err != nil {
+		return strings.Join(fv.Texts, ",")
+	}
+	return string(b)
+}
+
+// ValueType returns the type name of the value.
+func (fv *JSONs) ValueType() string {
+	return "jsonSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *JSONs) Type() string {
+	return fv.ValueType()
+}
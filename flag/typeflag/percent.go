@@ -0,0 +1,70 @@
+package typeflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Percent is a `flag.Value` for percentage arguments such as `50%` or `0.5`.
+// The `Value` field holds the fraction, so both examples result in 0.5.
+// Values outside of [0, 1] are rejected unless `AllowOverflow` is set.
+type Percent struct {
+	driver.Meta
+
+	AllowOverflow bool
+
+	Value float64
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Percent) Help() string {
+	if fv.AllowOverflow {
+		return "a percentage such as 50% or a fraction such as 0.5"
+	}
+	return "a percentage between 0% and 100%, or a fraction between 0 and 1"
+}
+
+// Set is flag.Value.Set
+func (fv *Percent) Set(v string) error {
+	s := strings.TrimSpace(v)
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+		scale = 100
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf(`not a valid percentage: "%s"`, v)
+	}
+	n /= scale
+	if !fv.AllowOverflow && (n < 0 || n > 1) {
+		return fmt.Errorf(`"%s" must be between 0%% and 100%%`, v)
+	}
+	fv.Value = n
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Percent) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed value as a percentage.
+func (fv *Percent) ValueString() string {
+	return strconv.FormatFloat(fv.Value*100, 'g', -1, 64) + "%"
+}
+
+// ValueType returns the type name of the value.
+func (fv *Percent) ValueType() string {
+	return "percent"
+}
+
+// Type is pflag.Value.Type
+func (fv *Percent) Type() string {
+	return fv.ValueType()
+}
@@ -0,0 +1,46 @@
+package typeflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		arg           string
+		allowOverflow bool
+		want          float64
+		wantString    string
+		wantErr       bool
+	}{
+		{arg: "50%", want: 0.5, wantString: "50%"},
+		{arg: " 12.5 % ", want: 0.125, wantString: "12.5%"},
+		{arg: "0.25", want: 0.25, wantString: "25%"},
+		{arg: "0%", want: 0, wantString: "0%"},
+		{arg: "100%", want: 1, wantString: "100%"},
+		{arg: "1", want: 1, wantString: "100%"},
+		{arg: "150%", wantErr: true},
+		{arg: "-1%", wantErr: true},
+		{arg: "1.5", wantErr: true},
+		{arg: "150%", allowOverflow: true, want: 1.5, wantString: "150%"},
+		{arg: "-0.5", allowOverflow: true, want: -0.5, wantString: "-50%"},
+		{arg: "half", wantErr: true},
+		{arg: "%", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &typeflag.Percent{AllowOverflow: tt.allowOverflow}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if fv.Value != tt.want || fv.ValueString() != tt.wantString {
+				t.Errorf("Set(%q) = %v (%s), want %v (%s)", tt.arg, fv.Value, fv.ValueString(), tt.want, tt.wantString)
+			}
+		})
+	}
+}
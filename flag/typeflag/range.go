@@ -0,0 +1,165 @@
+package typeflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Range is a `flag.Value` for integer range arguments such as `1-10`, `5..` or `..10`.
+// Either end may be left open; open ends are filled in from `Min` and `Max` when set.
+// The `Base` and `BitSize` fields are used for parsing when set.
+// If `Min` or `Max` are set, both ends of the range must lie within them.
+type Range struct {
+	driver.Meta
+
+	Base    int
+	BitSize int
+	Min     *int64
+	Max     *int64
+
+	Value struct {
+		From *int64
+		To   *int64
+	}
+	Text string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Range) Help() string {
+	var base, bitSize, bounds string
+	if fv.Base != 0 {
+		base = fmt.Sprintf("base %d ", fv.Base)
+	}
+	if fv.BitSize != 0 {
+		bitSize = fmt.Sprintf("%d-bit ", fv.BitSize)
+	}
+	switch {
+	case fv.Min != nil && fv.Max != nil:
+		bounds = fmt.Sprintf(" within %d-%d", *fv.Min, *fv.Max)
+	case fv.Min != nil:
+		bounds = fmt.Sprintf(" of at least %d", *fv.Min)
+	case fv.Max != nil:
+		bounds = fmt.Sprintf(" of at most %d", *fv.Max)
+	}
+	return fmt.Sprintf("a range of %s%sintegers%s (FROM-TO, FROM.. or ..TO)", bitSize, base, bounds)
+}
+
+// Set is flag.Value.Set
+func (fv *Range) Set(v string) error {
+	fromText, toText, ok := splitRange(strings.TrimSpace(v))
+	if !ok {
+		return fmt.Errorf(`not a valid range: "%s"`, v)
+	}
+	from, err := fv.parseEnd(fromText, fv.Min)
+	if err != nil {
+		return err
+	}
+	to, err := fv.parseEnd(toText, fv.Max)
+	if err != nil {
+		return err
+	}
+	if from != nil && to != nil && *from > *to {
+		return fmt.Errorf(`"%s" must not end before it starts`, v)
+	}
+	for _, n := range []*int64{from, to} {
+		if n == nil {
+			continue
+		}
+		if fv.Min != nil && *n < *fv.Min {
+			return fmt.Errorf(`"%s" must not extend below %d`, v, *fv.Min)
+		}
+		if fv.Max != nil && *n > *fv.Max {
+			return fmt.Errorf(`"%s" must not extend above %d`, v, *fv.Max)
+		}
+	}
+	fv.Value.From = from
+	fv.Value.To = to
+	fv.Text = v
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *Range) String() string {
+	return fv.Text
+}
+
+// ValueString returns the parsed range in base 10, with open ends left empty.
+func (fv *Range) ValueString() string {
+	var from, to string
+	if fv.Value.From != nil {
+		from = strconv.FormatInt(*fv.Value.From, 10)
+	}
+	if fv.Value.To != nil {
+		to = strconv.FormatInt(*fv.Value.To, 10)
+	}
+	if from == "" && to == "" {
+		return ""
+	}
+	return from + ".." + to
+}
+
+// ValueType returns the type name of the value.
+func (fv *Range) ValueType() string {
+	return "intRange"
+}
+
+// Type is pflag.Value.Type
+func (fv *Range) Type() string {
+	return fv.ValueType()
+}
+
+// Contains returns whether n lies within the range. Open ends are unbounded.
+func (fv *Range) Contains(n int64) bool {
+	if fv.Value.From != nil && n < *fv.Value.From {
+		return false
+	}
+	if fv.Value.To != nil && n > *fv.Value.To {
+		return false
+	}
+	return true
+}
+
+func (fv *Range) parseEnd(text string, fallback *int64) (*int64, error) {
+	if text == "" {
+		if fallback == nil {
+			return nil, nil
+		}
+		n := *fallback
+		return &n, nil
+	}
+	base := fv.Base
+	if base == 0 {
+		base = 10
+	}
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	n, err := strconv.ParseInt(text, base, bitSize)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// splitRange splits v at ".." or, failing that, at the first "-" that is not a sign.
+func splitRange(v string) (string, string, bool) {
+	if i := strings.Index(v, ".."); i >= 0 {
+		from, to := strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+2:])
+		return from, to, from != "" || to != ""
+	}
+	start := 0
+	if strings.HasPrefix(v, "-") || strings.HasPrefix(v, "+") {
+		start = 1
+	}
+	i := strings.Index(v[start:], "-")
+	if i < 0 {
+		return "", "", false
+	}
+	i += start
+	from, to := strings.TrimSpace(v[:i]), strings.TrimSpace(v[i+1:])
+	return from, to, from != "" && to != ""
+}
@@ -0,0 +1,68 @@
+package typeflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestRange(t *testing.T) {
+	n := func(v int64) *int64 { return &v }
+	tests := []struct {
+		arg     string
+		fv      typeflag.Range
+		want    string
+		wantErr bool
+	}{
+		{arg: "1-10", want: "1..10"},
+		{arg: "1..10", want: "1..10"},
+		{arg: " 1 - 10 ", want: "1..10"},
+		{arg: "-5--1", want: "-5..-1"},
+		{arg: "-5..5", want: "-5..5"},
+		{arg: "+1-+2", want: "1..2"},
+		{arg: "5..", want: "5.."},
+		{arg: "..10", want: "..10"},
+		{arg: "7-7", want: "7..7"},
+		{arg: "0x10..0x20", fv: typeflag.Range{Base: 0}, wantErr: true},
+		{arg: "10..20", fv: typeflag.Range{Base: 16}, want: "16..32"},
+		{arg: "5..", fv: typeflag.Range{Max: n(9)}, want: "5..9"},
+		{arg: "..5", fv: typeflag.Range{Min: n(1)}, want: "1..5"},
+		{arg: "0..5", fv: typeflag.Range{Min: n(1)}, wantErr: true},
+		{arg: "1..10", fv: typeflag.Range{Max: n(9)}, wantErr: true},
+		{arg: "100..200", fv: typeflag.Range{BitSize: 8}, wantErr: true},
+		{arg: "10-1", wantErr: true},
+		{arg: "..", wantErr: true},
+		{arg: "5", wantErr: true},
+		{arg: "-5", wantErr: true},
+		{arg: "5-", wantErr: true},
+		{arg: "a..b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := tt.fv
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := fv.ValueString(); got != tt.want {
+				t.Errorf("Set(%q) = %s, want %s", tt.arg, got, tt.want)
+			}
+		})
+	}
+
+	fv := &typeflag.Range{}
+	if err := fv.Set("5.."); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		n    int64
+		want bool
+	}{{4, false}, {5, true}, {1 << 40, true}} {
+		if got := fv.Contains(tt.n); got != tt.want {
+			t.Errorf("Contains(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package typeflag
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Regexp is a `flag.Value` for regular expression arguments, compiled at Set time.
+// If `POSIX` is set, expressions are compiled using regexp.CompilePOSIX.
+type Regexp struct {
+	driver.Meta
+
+	POSIX bool
+
+	Value *regexp.Regexp
+	Text  string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Regexp) Help() string {
+	if fv.POSIX {
+		return "a POSIX regular expression"
+	}
+	return "a regular expression"
+}
+
+// Set is flag.Value.Set
+func (fv *Regexp) Set(v string) error {
+	re, err := compileRegexp(v, fv.POSIX)
+	if err == nil {
+		fv.Value = re
+		fv.Text = v
+		fv.MarkChanged()
+	}
+	return err
+}
+
+func (fv *Regexp) String() string {
+	return fv.Text
+}
+
+// ValueString returns the source text of the compiled expression.
+func (fv *Regexp) ValueString() string {
+	if fv.Value == nil {
+		return ""
+	}
+	return fv.Value.String()
+}
+
+// ValueType returns the type name of the value.
+func (fv *Regexp) ValueType() string {
+	return "regexp"
+}
+
+// Type is pflag.Value.Type
+func (fv *Regexp) Type() string {
+	return fv.ValueType()
+}
+
+// Regexps is a `flag.Value` for regular expression arguments, compiled at Set time.
+// If `POSIX` is set, expressions are compiled using regexp.CompilePOSIX.
+type Regexps struct {
+	driver.Meta
+
+	POSIX bool
+
+	Values []*regexp.Regexp
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Regexps) Help() string {
+	if fv.POSIX {
+		return "a POSIX regular expression"
+	}
+	return "a regular expression"
+}
+
+// Set is flag.Value.Set
+func (fv *Regexps) Set(v string) error {
+	re, err := compileRegexp(v, fv.POSIX)
+	if err == nil {
+		fv.Values = append(fv.Values, re)
+		fv.Texts = append(fv.Texts, v)
+		fv.MarkChanged()
+	}
+	return err
+}
+
+func (fv *Regexps) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the source texts of the compiled expressions.
+func (fv *Regexps) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, re := range fv.Values {
+		texts[i] = re.String()
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Regexps) ValueType() string {
+	return "regexpSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Regexps) Type() string {
+	return fv.ValueType()
+}
+
+// MatchString returns whether s matches any of the expressions.
+func (fv *Regexps) MatchString(s string) bool {
+	for _, re := range fv.Values {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileRegexp(v string, posix bool) (*regexp.Regexp, error) {
+	if posix {
+		return regexp.CompilePOSIX(v)
+	}
+	return regexp.Compile(v)
+}
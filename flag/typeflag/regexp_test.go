@@ -0,0 +1,66 @@
+package typeflag_test
+
+import (
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestRegexp(t *testing.T) {
+	tests := []struct {
+		arg     string
+		posix   bool
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{arg: "a+", input: "baaab", want: "aaa"},
+		{arg: "(a|ab)(c|bcd)", input: "abcd", want: "abcd"},
+		{arg: "a|ab", input: "ab", want: "a"},
+		{arg: "a|ab", posix: true, input: "ab", want: "ab"},
+		{arg: `\d+`, input: "x42", want: "42"},
+		{arg: `\d+`, posix: true, wantErr: true},
+		{arg: "(", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &typeflag.Regexp{POSIX: tt.posix}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				if fv.Value != nil || fv.HasChanged() {
+					t.Error("failed Set changed the value")
+				}
+				return
+			}
+			if got := fv.Value.FindString(tt.input); got != tt.want {
+				t.Errorf("FindString(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if fv.String() != tt.arg || fv.ValueString() != tt.arg {
+				t.Errorf("String = %q, ValueString = %q", fv.String(), fv.ValueString())
+			}
+		})
+	}
+}
+
+func TestRegexps(t *testing.T) {
+	fv := &typeflag.Regexps{}
+	for _, arg := range []string{`^/api/`, `\.js$`} {
+		if err := fv.Set(arg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fv.Set("("); err == nil {
+		t.Error("Set accepted an invalid expression")
+	}
+	if got := fv.String(); got != `^/api/,\.js$` {
+		t.Errorf("String = %q", got)
+	}
+	for input, want := range map[string]bool{"/api/items": true, "/app.js": true, "/index.html": false} {
+		if got := fv.MatchString(input); got != want {
+			t.Errorf("MatchString(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package typeflag
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// RedactedText is printed in place of the value of a `Secret`.
+const RedactedText = "[redacted]"
+
+// Secret is a `flag.Value` for passwords, tokens and keys.
+// The argument `env:NAME` reads the secret from the environment variable NAME,
+// `file:PATH` reads it from the file at PATH (without trailing newlines), and
+// `literal:VALUE` or any other argument is taken as the secret itself.
+//
+// The secret never appears in `String`, `ValueString`, help output or formatted logs;
+// only the `Ref` of secrets read from the environment or a file is shown.
+// `Reset` zeroes the memory holding the secret.
+type Secret struct {
+	driver.Meta
+
+	Value []byte
+	Ref   string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Secret) Help() string {
+	return "a secret: a literal value, env:NAME or file:PATH"
+}
+
+// Set is flag.Value.Set
+func (fv *Secret) Set(v string) error {
+	var value []byte
+	ref := ""
+	switch {
+	case strings.HasPrefix(v, "env:"):
+		name := strings.TrimPrefix(v, "env:")
+		text, ok := os.LookupEnv(name)
+		if !ok {
+			return fmt.Errorf(`environment variable "%s" is not set`, name)
+		}
+		value, ref = []byte(text), v
+	case strings.HasPrefix(v, "file:"):
+		path := strings.TrimPrefix(v, "file:")
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		value, ref = bytes.TrimRight(b, "\r\n"), v
+	default:
+		value = []byte(strings.TrimPrefix(v, "literal:"))
+	}
+	fv.Reset()
+	fv.Value = value
+	fv.Ref = ref
+	fv.MarkChanged()
+	return nil
+}
+
+// Reset zeroes and releases the secret.
+func (fv *Secret) Reset() {
+	for i := range fv.Value {
+		fv.Value[i] = 0
+	}
+	fv.Value = nil
+	fv.Ref = ""
+}
+
+// Reveal returns the secret.
+func (fv *Secret) Reveal() string {
+	return string(fv.Value)
+}
+
+// Redacted returns the text printed in place of the secret.
+func (fv *Secret) Redacted() string {
+	if fv.Value == nil {
+		return ""
+	}
+	if fv.Ref != "" {
+		return RedactedText + " (" + fv.Ref + ")"
+	}
+	return RedactedText
+}
+
+func (fv *Secret) String() string {
+	return fv.Redacted()
+}
+
+// ValueString returns the redacted secret.
+func (fv *Secret) ValueString() string {
+	return fv.Redacted()
+}
+
+// ValueType returns the type name of the value.
+func (fv *Secret) ValueType() string {
+	return "secret"
+}
+
+// Type is pflag.Value.Type
+func (fv *Secret) Type() string {
+	return fv.ValueType()
+}
+
+// Format implements fmt.Formatter, so that the secret is redacted for all verbs.
+func (fv *Secret) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, fv.Redacted())
+}
+
+// MarshalJSON encodes the redacted secret.
+func (fv *Secret) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", fv.Redacted())), nil
+}
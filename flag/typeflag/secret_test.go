@@ -0,0 +1,79 @@
+package typeflag_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func TestSecret(t *testing.T) {
+	t.Setenv("APP_TOKEN", "from-env")
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\r\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		arg          string
+		want         string
+		wantRedacted string
+		wantErr      bool
+	}{
+		{arg: "hunter2", want: "hunter2", wantRedacted: "[redacted]"},
+		{arg: "literal:env:NOT_AN_ENV", want: "env:NOT_AN_ENV", wantRedacted: "[redacted]"},
+		{arg: "env:APP_TOKEN", want: "from-env", wantRedacted: "[redacted] (env:APP_TOKEN)"},
+		{arg: "file:" + path, want: "from-file", wantRedacted: "[redacted] (file:" + path + ")"},
+		{arg: "env:APP_MISSING", wantErr: true},
+		{arg: "file:" + path + ".missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			fv := &typeflag.Secret{}
+			err := fv.Set(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tt.arg, err, tt.wantErr)
+			}
+			if err != nil {
+				if fv.Value != nil || fv.HasChanged() {
+					t.Error("failed Set changed the value")
+				}
+				return
+			}
+			if got := fv.Reveal(); got != tt.want {
+				t.Errorf("Reveal = %q, want %q", got, tt.want)
+			}
+			if fv.Redacted() != tt.wantRedacted || fv.String() != tt.wantRedacted || fv.ValueString() != tt.wantRedacted {
+				t.Errorf("Redacted = %q, String = %q, ValueString = %q; want %q", fv.Redacted(), fv.String(), fv.ValueString(), tt.wantRedacted)
+			}
+		})
+	}
+}
+
+func TestSecretFormatting(t *testing.T) {
+	fv := &typeflag.Secret{}
+	if err := fv.Set("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		if got := fmt.Sprintf(verb, fv); got != typeflag.RedactedText {
+			t.Errorf("Sprintf(%q) = %q, want %q", verb, got, typeflag.RedactedText)
+		}
+	}
+	b, err := json.Marshal(struct{ Token *typeflag.Secret }{fv})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"Token":"[redacted]"}`; got != want {
+		t.Errorf("json = %s, want %s", got, want)
+	}
+
+	value := fv.Value
+	fv.Reset()
+	if fv.Value != nil || fv.Redacted() != "" || string(value) != "\x00\x00\x00\x00\x00\x00\x00" {
+		t.Errorf("after Reset: Value = %q, Redacted = %q, old memory = %q", fv.Value, fv.Redacted(), value)
+	}
+}
@@ -1,20 +1,24 @@
 package typeflag
 
-
 import (
 	"fmt"
 	"sort"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // Strings is a `flag.Value` for `string` arguments.
 type Strings struct {
+	driver.Meta
+
 	Values []string
 }
 
 // Set is flag.Value.Set
 func (fv *Strings) Set(v string) error {
 	fv.Values = append(fv.Values, v)
+	fv.MarkChanged()
 	return nil
 }
 
@@ -22,9 +26,46 @@ func (fv *Strings) String() string {
 	return strings.Join(fv.Values, ",")
 }
 
+// ValueString returns the collected strings.
+func (fv *Strings) ValueString() string {
+	return strings.Join(fv.Values, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Strings) ValueType() string {
+	return "stringSlice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Strings) Type() string {
+	return fv.ValueType()
+}
+
+// StringsCSV is a `flag.Value` for `string` arguments.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Separator` field is used instead of the comma when set.
+type StringsCSV = CSV[string, stringParser]
+
+type stringParser struct{}
+
+func (stringParser) Parse(v string) (string, error) { return v, nil }
+
+func (stringParser) Format(v string) string { return v }
+
+func (stringParser) Describe(plural bool) string {
+	if plural {
+		return "strings"
+	}
+	return "a string"
+}
+
+func (stringParser) TypeName() string { return "string" }
+
 // StringSet is a `flag.Value` for `string` arguments.
 // Only distinct values are returned.
 type StringSet struct {
+	driver.Meta
+
 	Value map[string]bool
 }
 
@@ -43,6 +84,7 @@ func (fv *StringSet) Set(v string) error {
 		fv.Value = make(map[string]bool)
 	}
 	fv.Value[v] = true
+	fv.MarkChanged()
 	return nil
 }
 
@@ -50,11 +92,28 @@ func (fv *StringSet) String() string {
 	return strings.Join(fv.Values(), ",")
 }
 
+// ValueString returns the distinct strings, sorted.
+func (fv *StringSet) ValueString() string {
+	return strings.Join(fv.Values(), ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *StringSet) ValueType() string {
+	return "stringSet"
+}
+
+// Type is pflag.Value.Type
+func (fv *StringSet) Type() string {
+	return fv.ValueType()
+}
+
 // StringSetCSV is a `flag.Value` for comma-separated string arguments.
 // If `Accumulate` is set, the values of all instances of the flag are accumulated.
 // The `Separator` field is used instead of the comma when set.
 // If `CaseSensitive` is set to `true` (default `false`), the comparison is case-sensitive.
 type StringSetCSV struct {
+	driver.Meta
+
 	Separator  string
 	Accumulate bool
 
@@ -89,9 +148,25 @@ func (fv *StringSetCSV) Set(v string) error {
 		fv.Value[part] = true
 		fv.Values = append(fv.Values, part)
 	}
+	fv.MarkChanged()
 	return nil
 }
 
 func (fv *StringSetCSV) String() string {
 	return strings.Join(fv.Values, ",")
 }
+
+// ValueString returns the distinct strings in order of appearance.
+func (fv *StringSetCSV) ValueString() string {
+	return strings.Join(fv.Values, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *StringSetCSV) ValueType() string {
+	return "stringSet"
+}
+
+// Type is pflag.Value.Type
+func (fv *StringSetCSV) Type() string {
+	return fv.ValueType()
+}
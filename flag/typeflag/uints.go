@@ -0,0 +1,162 @@
+package typeflag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Uints is a `flag.Value` for `uint` arguments.
+// The `Base` and `BitSize` fields are used for parsing when set.
+type Uints struct {
+	driver.Meta
+
+	Base    int
+	BitSize int
+
+	Values []uint64
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *Uints) Help() string {
+	var base, bitSize string
+	if fv.Base != 0 {
+		base = fmt.Sprintf("base %d ", fv.Base)
+	}
+	if fv.BitSize != 0 {
+		bitSize = fmt.Sprintf("%d-bit ", fv.BitSize)
+	}
+	return fmt.Sprintf("a %s%sunsigned integer", bitSize, base)
+}
+
+// Set is flag.Value.Set
+func (fv *Uints) Set(v string) error {
+	base := fv.Base
+	if base == 0 {
+		base = 10
+	}
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	n, err := strconv.ParseUint(v, base, bitSize)
+	if err == nil {
+		fv.Values = append(fv.Values, n)
+		fv.Texts = append(fv.Texts, v)
+		fv.MarkChanged()
+	}
+	return err
+}
+
+func (fv *Uints) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed integers in base 10.
+func (fv *Uints) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatUint(n, 10)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *Uints) ValueType() string {
+	return "uint64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *Uints) Type() string {
+	return fv.ValueType()
+}
+
+// UintsCSV is a `flag.Value` for comma-separated `uint` arguments.
+// If `Accumulate` is set, the values of all instances of the flag are accumulated.
+// The `Base` and `BitSize` fields are used for parsing when set.
+// The `Separator` field is used instead of the comma when set.
+type UintsCSV struct {
+	driver.Meta
+
+	Base       int
+	BitSize    int
+	Separator  string
+	Accumulate bool
+
+	Values []uint64
+	Texts  []string
+}
+
+// Help returns a string suitable for inclusion in a flag help message.
+func (fv *UintsCSV) Help() string {
+	var base, bitSize string
+	if fv.Base != 0 {
+		base = fmt.Sprintf("base %d ", fv.Base)
+	}
+	if fv.BitSize != 0 {
+		bitSize = fmt.Sprintf("%d-bit ", fv.BitSize)
+	}
+	separator := ","
+	if fv.Separator != "" {
+		separator = fv.Separator
+	}
+	return fmt.Sprintf("%q-separated list of %s%sunsigned integers", separator, bitSize, base)
+}
+
+// Set is flag.Value.Set
+func (fv *UintsCSV) Set(v string) error {
+	base := fv.Base
+	if base == 0 {
+		base = 10
+	}
+	bitSize := fv.BitSize
+	if bitSize == 0 {
+		bitSize = 64
+	}
+	separator := fv.Separator
+	if separator == "" {
+		separator = ","
+	}
+	if !fv.Accumulate {
+		fv.Values = fv.Values[:0]
+		fv.Texts = fv.Texts[:0]
+	}
+	parts := strings.Split(v, separator)
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		n, err := strconv.ParseUint(part, base, bitSize)
+		if err != nil {
+			return err
+		}
+		fv.Values = append(fv.Values, n)
+		fv.Texts = append(fv.Texts, part)
+	}
+	fv.MarkChanged()
+	return nil
+}
+
+func (fv *UintsCSV) String() string {
+	return strings.Join(fv.Texts, ",")
+}
+
+// ValueString returns the parsed integers in base 10.
+func (fv *UintsCSV) ValueString() string {
+	texts := make([]string, len(fv.Values))
+	for i, n := range fv.Values {
+		texts[i] = strconv.FormatUint(n, 10)
+	}
+	return strings.Join(texts, ",")
+}
+
+// ValueType returns the type name of the value.
+func (fv *UintsCSV) ValueType() string {
+	return "uint64Slice"
+}
+
+// Type is pflag.Value.Type
+func (fv *UintsCSV) Type() string {
+	return fv.ValueType()
+}
@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 // WrapPointer wraps a pointer to a `flag.Value`
 // This can be used to switch between different argument parsers.
 type WrapPointer struct {
+	driver.Meta
+
 	Value *flag.Value
 }
 
@@ -27,7 +31,11 @@ func (fv *WrapPointer) Help() string {
 
 // Set is flag.Value.Set
 func (fv *WrapPointer) Set(v string) error {
-	return (*fv.Value).Set(v)
+	err := (*fv.Value).Set(v)
+	if err == nil {
+		fv.MarkChanged()
+	}
+	return err
 }
 
 func (fv WrapPointer) String() string {
@@ -37,6 +45,27 @@ func (fv WrapPointer) String() string {
 	return (*fv.Value).String()
 }
 
+// ValueString returns the `ValueString` of the pointed-to value.
+func (fv *WrapPointer) ValueString() string {
+	if fv.Value == nil || *fv.Value == nil {
+		return ""
+	}
+	return driver.ValueString(*fv.Value)
+}
+
+// ValueType returns the type name of the pointed-to value.
+func (fv *WrapPointer) ValueType() string {
+	if fv.Value == nil || *fv.Value == nil {
+		return "value"
+	}
+	return driver.ValueType(*fv.Value)
+}
+
+// Type is pflag.Value.Type
+func (fv *WrapPointer) Type() string {
+	return fv.ValueType()
+}
+
 // WrapFunc wraps a nullary function returning a `flag.Value`
 // This can be used to switch between different argument parsers.
 type WrapFunc func() flag.Value
@@ -66,8 +95,53 @@ func (fv WrapFunc) String() string {
 	return fv().String()
 }
 
+// HasChanged returns the `HasChanged` of the current value, if it implements `driver.Flag`.
+func (fv WrapFunc) HasChanged() bool {
+	if fv == nil {
+		return false
+	}
+	if f, ok := fv().(driver.Flag); ok {
+		return f.HasChanged()
+	}
+	return false
+}
+
+// Name returns the `Name` of the current value, if it implements `driver.Flag`.
+func (fv WrapFunc) Name() string {
+	if fv == nil {
+		return ""
+	}
+	if f, ok := fv().(driver.Flag); ok {
+		return f.Name()
+	}
+	return ""
+}
+
+// ValueString returns the `ValueString` of the current value.
+func (fv WrapFunc) ValueString() string {
+	if fv == nil {
+		return ""
+	}
+	return driver.ValueString(fv())
+}
+
+// ValueType returns the type name of the current value.
+func (fv WrapFunc) ValueType() string {
+	if fv == nil {
+		return "value"
+	}
+	return driver.ValueType(fv())
+}
+
+// Type is pflag.Value.Type
+func (fv WrapFunc) Type() string {
+	return fv.ValueType()
+}
+
 // Wrap wraps a `flag.Value` and calls `Updated` each time the underlying value is set.
 type Wrap struct {
+	driver.Meta
+
 	Value   flag.Value
 	Updated func()
 }
@@ -89,6 +163,7 @@ func (fv *Wrap) Help() string {
 func (fv *Wrap) Set(v string) error {
 	err := fv.Value.Set(v)
 	if err == nil {
+		fv.MarkChanged()
 		fv.Updated()
 	}
 	return err
@@ -101,9 +176,26 @@ func (fv *Wrap) String() string {
 	return fv.Value.String()
 }
 
+// ValueString returns the `ValueString` of the wrapped value.
+func (fv *Wrap) ValueString() string {
+	return driver.ValueString(fv.Value)
+}
+
+// ValueType returns the type name of the wrapped value.
+func (fv *Wrap) ValueType() string {
+	return driver.ValueType(fv.Value)
+}
+
+// Type is pflag.Value.Type
+func (fv *Wrap) Type() string {
+	return fv.ValueType()
+}
+
 // WrapCSV wraps a `flag.Value` and calls `UpdatedOne` after each single value and `UpdatedAll` after each CSV batch.
 // The `Separator` field is used instead of the comma when set.
 type WrapCSV struct {
+	driver.Meta
+
 	Value      flag.Value
 	Separator  string
 	UpdatedOne func()
@@ -145,6 +237,7 @@ func (fv *WrapCSV) Set(v string) error {
 			fv.UpdatedOne()
 		}
 	}
+	fv.MarkChanged()
 	if fv.UpdatedAll != nil {
 		fv.UpdatedAll()
 	}
@@ -160,3 +253,18 @@ func (fv *WrapCSV) String() string {
 	}
 	return fv.Value.String()
 }
+
+// ValueString returns the `ValueString` of the wrapped value.
+func (fv *WrapCSV) ValueString() string {
+	return driver.ValueString(fv.Value)
+}
+
+// ValueType returns the type name of the wrapped value.
+func (fv *WrapCSV) ValueType() string {
+	return driver.ValueType(fv.Value)
+}
+
+// Type is pflag.Value.Type
+func (fv *WrapCSV) Type() string {
+	return fv.ValueType()
+}
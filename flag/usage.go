@@ -0,0 +1,269 @@
+package flag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Usage renders help output for the flags of a flag set, appending the `Help()` text
+// and default of each value. It writes plain text wrapped to the terminal width,
+// Markdown, or a man page in roff format.
+//
+// Flags are listed by group, with flags without a group first under "Flags".
+// The value of the `Width` field is used for wrapping when set, otherwise $COLUMNS or 80.
+// If `Resolver` is set, the source of values that were not defaults is reported as well.
+// Defaults of `driver.Sensitive` values are never shown.
+type Usage struct {
+	Name        string
+	Description string
+	Groups      map[string]string
+	Width       int
+	Resolver    *Resolver
+}
+
+// usageEntry is the rendering-independent description of one flag.
+type usageEntry struct {
+	name        string
+	typ         string
+	description string
+	def         string
+	source      driver.Source
+}
+
+type usageGroup struct {
+	title   string
+	entries []usageEntry
+}
+
+// Func returns a function suitable for `flag.FlagSet.Usage` that writes text usage to the flag set's output.
+func (u *Usage) Func(fs *flag.FlagSet) func() {
+	return func() {
+		u.Write(fs.Output(), fs)
+	}
+}
+
+// Write writes plain text usage for fs to w.
+func (u *Usage) Write(w io.Writer, fs *flag.FlagSet) error {
+	width := u.width()
+	var b strings.Builder
+	if u.Name != "" {
+		fmt.Fprintf(&b, "Usage of %s:\n", u.Name)
+	}
+	if u.Description != "" {
+		b.WriteString(wrap(u.Description, width, ""))
+	}
+	for _, g := range u.groups(fs) {
+		fmt.Fprintf(&b, "\n%s:\n", g.title)
+		for _, e := range g.entries {
+			fmt.Fprintf(&b, "  -%s", e.name)
+			if e.typ != "" {
+				fmt.Fprintf(&b, " %s", e.typ)
+			}
+			b.WriteString("\n")
+			b.WriteString(wrap(e.text(), width, "    \t"))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes usage for fs to w as Markdown, with one table per group.
+func (u *Usage) WriteMarkdown(w io.Writer, fs *flag.FlagSet) error {
+	var b strings.Builder
+	if u.Name != "" {
+		fmt.Fprintf(&b, "# %s\n\n", u.Name)
+	}
+	if u.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", u.Description)
+	}
+	for _, g := range u.groups(fs) {
+		fmt.Fprintf(&b, "## %s\n\n", g.title)
+		b.WriteString("| Flag | Type | Description | Default |\n")
+		b.WriteString("|------|------|-------------|---------|\n")
+		for _, e := range g.entries {
+			def := ""
+			if e.def != "" {
+				def = "`" + markdownEscape(e.def) + "`"
+			}
+			typ := ""
+			if e.typ != "" {
+				typ = "`" + e.typ + "`"
+			}
+			fmt.Fprintf(&b, "| `-%s` | %s | %s | %s |\n", e.name, typ, markdownEscape(e.description), def)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMan writes usage for fs to w as a section 1 man page in roff format.
+func (u *Usage) WriteMan(w io.Writer, fs *flag.FlagSet) error {
+	name := u.Name
+	if name == "" {
+		name = fs.Name()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1\n", roffEscape(strings.ToUpper(name)))
+	b.WriteString(".SH NAME\n")
+	b.WriteString(roffEscape(name))
+	if u.Description != "" {
+		b.WriteString(" \\- " + roffEscape(u.Description))
+	}
+	b.WriteString("\n.SH SYNOPSIS\n")
+	fmt.Fprintf(&b, "\\fB%s\\fR [\\fIOPTIONS\\fR]\n", roffEscape(name))
+	b.WriteString(".SH OPTIONS\n")
+	for _, g := range u.groups(fs) {
+		fmt.Fprintf(&b, ".SS %s\n", roffEscape(g.title))
+		for _, e := range g.entries {
+			b.WriteString(".TP\n")
+			fmt.Fprintf(&b, "\\fB\\-%s\\fR", roffEscape(e.name))
+			if e.typ != "" {
+				fmt.Fprintf(&b, " \\fI%s\\fR", roffEscape(e.typ))
+			}
+			b.WriteString("\n")
+			b.WriteString(roffEscape(e.text()))
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (u *Usage) width() int {
+	if u.Width > 0 {
+		return u.Width
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+func (u *Usage) groups(fs *flag.FlagSet) []usageGroup {
+	byTitle := map[string]*usageGroup{}
+	var titles []string
+	fs.VisitAll(func(f *flag.Flag) {
+		title := u.Groups[f.Name]
+		if title == "" {
+			title = "Flags"
+		}
+		g, ok := byTitle[title]
+		if !ok {
+			g = &usageGroup{title: title}
+			byTitle[title] = g
+			titles = append(titles, title)
+		}
+		g.entries = append(g.entries, u.entry(f))
+	})
+	sort.SliceStable(titles, func(i, j int) bool {
+		if titles[j] == "Flags" {
+			return false
+		}
+		return titles[i] == "Flags" || titles[i] < titles[j]
+	})
+	groups := make([]usageGroup, len(titles))
+	for i, title := range titles {
+		groups[i] = *byTitle[title]
+	}
+	return groups
+}
+
+func (u *Usage) entry(f *flag.Flag) usageEntry {
+	typ, usage := flag.UnquoteUsage(f)
+	if fv, ok := f.Value.(driver.Flag); ok {
+		typ = fv.ValueType()
+	}
+	description := usage
+	if helper, ok := f.Value.(interface {
+		Help() string
+	}); ok {
+		if help := helper.Help(); help != "" {
+			if description != "" {
+				description += "; "
+			}
+			description += help
+		}
+	}
+	e := usageEntry{name: f.Name, typ: typ, description: description}
+	if _, ok := f.Value.(driver.Sensitive); !ok && !isZeroDefault(f.DefValue) {
+		e.def = f.DefValue
+	}
+	e.source = driver.SourceOf(f.Value)
+	if u.Resolver != nil && u.Resolver.sources != nil {
+		e.source = u.Resolver.Source(f.Name)
+	}
+	return e
+}
+
+// text returns the description followed by the default and the source of the value.
+func (e usageEntry) text() string {
+	text := e.description
+	if e.def != "" {
+		text += fmt.Sprintf(" (default %q)", e.def)
+	}
+	if e.source != "" && e.source != driver.SourceDefault {
+		text += fmt.Sprintf(" [set from %s]", e.source)
+	}
+	return strings.TrimSpace(text)
+}
+
+func isZeroDefault(def string) bool {
+	switch def {
+	case "", "0", "false", "[]", "<nil>", "map[]":
+		return true
+	}
+	return false
+}
+
+// wrap wraps text to lines of at most width columns (counting the indent), each prefixed with indent.
+// A tab in the indent is counted as reaching column 8.
+func wrap(text string, width int, indent string) string {
+	indentWidth := len(strings.Replace(indent, "\t", "", -1))
+	if strings.Contains(indent, "\t") {
+		indentWidth = 8
+	}
+	limit := width - indentWidth
+	if limit < 20 {
+		limit = 20
+	}
+	var b strings.Builder
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len(line)+1+len(word) > limit {
+				b.WriteString(indent + line + "\n")
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		b.WriteString(indent + line + "\n")
+	}
+	return b.String()
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+func roffEscape(s string) string {
+	s = strings.NewReplacer("\\", "\\e", "-", "\\-").Replace(s)
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			line = "\\&" + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
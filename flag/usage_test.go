@@ -0,0 +1,181 @@
+package flag_test
+
+import (
+	goflag "flag"
+	"strings"
+	"testing"
+
+	"github.com/gofunct/functional/flag"
+	"github.com/gofunct/functional/flag/constflag"
+	"github.com/gofunct/functional/flag/typeflag"
+)
+
+func newUsageFlagSet() (*goflag.FlagSet, *flag.Usage) {
+	fs := goflag.NewFlagSet("tool", goflag.ContinueOnError)
+	fs.String("addr", ":8080", "listen `address`")
+	fs.Bool("verbose", false, "log every request, including the health checks sent by the load balancer")
+	format := &constflag.Enum{Choices: []string{"text", "json"}, Default: "text"}
+	fs.Var(format, "format", "log format | style")
+	token := &typeflag.Secret{Value: []byte("hunter2")}
+	fs.Var(token, "token", "API token")
+	fs.String("dot", "", ".start of a line")
+	fs.String("quote", "", "'quoted' text")
+	fs.String("lines", "", "first line\n.second line with C:\\path")
+	u := &flag.Usage{
+		Name:        "tool",
+		Description: "tool serves -things-.",
+		Groups:      map[string]string{"dot": "Roff", "lines": "Roff", "quote": "Roff", "token": "Auth"},
+		Width:       40,
+	}
+	return fs, u
+}
+
+func TestUsageWrite(t *testing.T) {
+	fs, u := newUsageFlagSet()
+	var b strings.Builder
+	if err := u.Write(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	want := `Usage of tool:
+tool serves -things-.
+
+Flags:
+  -addr address
+    	listen address (default ":8080")
+  -format enum
+    	log format | style; one of [text
+    	json]
+  -verbose
+    	log every request, including the
+    	health checks sent by the load
+    	balancer
+
+Auth:
+  -token secret
+    	API token; a secret: a literal
+    	value, env:NAME or file:PATH
+
+Roff:
+  -dot string
+    	.start of a line
+  -lines string
+    	first line
+    	.second line with C:\path
+  -quote string
+    	'quoted' text
+`
+	if got := b.String(); got != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUsageWriteMarkdown(t *testing.T) {
+	fs, u := newUsageFlagSet()
+	var b strings.Builder
+	if err := u.WriteMarkdown(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	want := "# tool\n\n" +
+		"tool serves -things-.\n\n" +
+		"## Flags\n\n" +
+		"| Flag | Type | Description | Default |\n" +
+		"|------|------|-------------|---------|\n" +
+		"| `-addr` | `address` | listen address | `:8080` |\n" +
+		"| `-format` | `enum` | log format \\| style; one of [text json] |  |\n" +
+		"| `-verbose` |  | log every request, including the health checks sent by the load balancer |  |\n" +
+		"\n" +
+		"## Auth\n\n" +
+		"| Flag | Type | Description | Default |\n" +
+		"|------|------|-------------|---------|\n" +
+		"| `-token` | `secret` | API token; a secret: a literal value, env:NAME or file:PATH |  |\n" +
+		"\n" +
+		"## Roff\n\n" +
+		"| Flag | Type | Description | Default |\n" +
+		"|------|------|-------------|---------|\n" +
+		"| `-dot` | `string` | .start of a line |  |\n" +
+		"| `-lines` | `string` | first line .second line with C:\\path |  |\n" +
+		"| `-quote` | `string` | 'quoted' text |  |\n" +
+		"\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUsageWriteMan(t *testing.T) {
+	fs, u := newUsageFlagSet()
+	var b strings.Builder
+	if err := u.WriteMan(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	want := `.TH TOOL 1
+.SH NAME
+tool \- tool serves \-things\-.
+.SH SYNOPSIS
+\fBtool\fR [\fIOPTIONS\fR]
+.SH OPTIONS
+.SS Flags
+.TP
+\fB\-addr\fR \fIaddress\fR
+listen address (default ":8080")
+.TP
+\fB\-format\fR \fIenum\fR
+log format | style; one of [text json]
+.TP
+\fB\-verbose\fR
+log every request, including the health checks sent by the load balancer
+.SS Auth
+.TP
+\fB\-token\fR \fIsecret\fR
+API token; a secret: a literal value, env:NAME or file:PATH
+.SS Roff
+.TP
+\fB\-dot\fR \fIstring\fR
+\&.start of a line
+.TP
+\fB\-lines\fR \fIstring\fR
+first line
+\&.second line with C:\epath
+.TP
+\fB\-quote\fR \fIstring\fR
+\&'quoted' text
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteMan() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUsageHidesSensitiveDefaults(t *testing.T) {
+	fs, u := newUsageFlagSet()
+	var b strings.Builder
+	if err := u.Write(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.WriteMarkdown(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.WriteMan(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"hunter2", typeflag.RedactedText} {
+		if strings.Contains(b.String(), leak) {
+			t.Errorf("usage contains %q:\n%s", leak, b.String())
+		}
+	}
+}
+
+func TestUsageSource(t *testing.T) {
+	fs := goflag.NewFlagSet("tool", goflag.ContinueOnError)
+	format := &constflag.Enum{Choices: []string{"text", "json"}, Default: "text"}
+	fs.Var(format, "format", "log format")
+	if err := fs.Parse([]string{"-format", "json"}); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := (&flag.Usage{Width: 80}).Write(&b, fs); err != nil {
+		t.Fatal(err)
+	}
+	want := "\nFlags:\n  -format enum\n    \tlog format; one of [text json] [set from flag]\n"
+	if got := b.String(); got != want {
+		t.Errorf("Write() = %q, want %q", got, want)
+	}
+}
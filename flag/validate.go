@@ -0,0 +1,169 @@
+package flag
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/gofunct/functional/flag/driver"
+)
+
+// Validator checks relationships between the flags of a parsed flag set:
+// required flags, mutually exclusive flags, "at least one of" groups,
+// and flags that require others. All violations are reported together.
+//
+// A flag counts as set if it was set on the flag set, either on the command line
+// or through `Resolver`, or if its value reports `HasChanged`.
+//
+// Example:
+//
+//  v := new(flag.Validator).
+//  	Required("addr").
+//  	MutuallyExclusive("json", "yaml").
+//  	Requires("tls-key", "tls-cert")
+//  if err := v.Validate(fs); err != nil {
+//  	return err
+//  }
+type Validator struct {
+	rules []rule
+}
+
+type rule struct {
+	names []string
+	check func(set map[string]bool) error
+}
+
+// ValidationErrors lists every constraint violated in a call to `Validator.Validate`.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	texts := make([]string, len(e))
+	for i, err := range e {
+		texts[i] = err.Error()
+	}
+	return strings.Join(texts, "; ")
+}
+
+// Required requires each of the named flags to be set.
+func (v *Validator) Required(names ...string) *Validator {
+	for _, name := range names {
+		name := name
+		v.add([]string{name}, func(set map[string]bool) error {
+			if !set[name] {
+				return fmt.Errorf("flag -%s is required", name)
+			}
+			return nil
+		})
+	}
+	return v
+}
+
+// MutuallyExclusive allows at most one of the named flags to be set.
+func (v *Validator) MutuallyExclusive(names ...string) *Validator {
+	return v.add(names, func(set map[string]bool) error {
+		if given := setFlags(set, names); len(given) > 1 {
+			return fmt.Errorf("flags %s are mutually exclusive, but %s were given", flagList(names, "and"), flagList(given, "and"))
+		}
+		return nil
+	})
+}
+
+// AtLeastOne requires at least one of the named flags to be set.
+func (v *Validator) AtLeastOne(names ...string) *Validator {
+	return v.add(names, func(set map[string]bool) error {
+		if len(setFlags(set, names)) == 0 {
+			return fmt.Errorf("one of the flags %s is required", flagList(names, "or"))
+		}
+		return nil
+	})
+}
+
+// ExactlyOne requires exactly one of the named flags to be set.
+func (v *Validator) ExactlyOne(names ...string) *Validator {
+	return v.AtLeastOne(names...).MutuallyExclusive(names...)
+}
+
+// Requires requires the flags in required to be set whenever the flag name is set.
+func (v *Validator) Requires(name string, required ...string) *Validator {
+	return v.add(append([]string{name}, required...), func(set map[string]bool) error {
+		if !set[name] {
+			return nil
+		}
+		var missing []string
+		for _, r := range required {
+			if !set[r] {
+				missing = append(missing, r)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("flag -%s requires %s", name, flagList(missing, "and"))
+		}
+		return nil
+	})
+}
+
+// Validate checks all constraints against fs and returns a `ValidationErrors` listing
+// every violation, or nil. Constraints naming flags that are not defined on fs are reported as well.
+func (v *Validator) Validate(fs *flag.FlagSet) error {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	fs.VisitAll(func(f *flag.Flag) {
+		if fv, ok := f.Value.(driver.Flag); ok && fv.HasChanged() {
+			set[f.Name] = true
+		}
+	})
+
+	var errs ValidationErrors
+	unknown := make(map[string]bool)
+	for _, r := range v.rules {
+		defined := true
+		for _, name := range r.names {
+			if fs.Lookup(name) == nil {
+				defined = false
+				if !unknown[name] {
+					unknown[name] = true
+					errs = append(errs, fmt.Errorf("flag -%s is not defined", name))
+				}
+			}
+		}
+		if !defined {
+			continue
+		}
+		if err := r.check(set); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (v *Validator) add(names []string, check func(set map[string]bool) error) *Validator {
+	v.rules = append(v.rules, rule{names: names, check: check})
+	return v
+}
+
+func setFlags(set map[string]bool, names []string) []string {
+	var given []string
+	for _, name := range names {
+		if set[name] {
+			given = append(given, name)
+		}
+	}
+	return given
+}
+
+// flagList formats names as "-a, -b and -c", using conj as the last separator.
+func flagList(names []string, conj string) string {
+	texts := make([]string, len(names))
+	for i, name := range names {
+		texts[i] = "-" + name
+	}
+	if len(texts) < 2 {
+		return strings.Join(texts, "")
+	}
+	return strings.Join(texts[:len(texts)-1], ", ") + " " + conj + " " + texts[len(texts)-1]
+}
@@ -0,0 +1,96 @@
+package flag_test
+
+import (
+	"errors"
+	goflag "flag"
+	"testing"
+
+	"github.com/gofunct/functional/flag"
+	"github.com/gofunct/functional/flag/constflag"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name      string
+		validator *flag.Validator
+		args      []string
+		want      string
+	}{
+		{"required set", new(flag.Validator).Required("a"), []string{"-a", "x"}, ""},
+		{"required missing", new(flag.Validator).Required("a", "b"), []string{"-a", "x"}, "flag -b is required"},
+		{"required both missing", new(flag.Validator).Required("a", "b"), nil, "flag -a is required; flag -b is required"},
+		{"mutually exclusive one", new(flag.Validator).MutuallyExclusive("a", "b", "c"), []string{"-b", "x"}, ""},
+		{"mutually exclusive two", new(flag.Validator).MutuallyExclusive("a", "b", "c"), []string{"-a", "x", "-c", "y"}, "flags -a, -b and -c are mutually exclusive, but -a and -c were given"},
+		{"at least one none", new(flag.Validator).AtLeastOne("a", "b", "c"), nil, "one of the flags -a, -b or -c is required"},
+		{"at least one two", new(flag.Validator).AtLeastOne("a", "b", "c"), []string{"-a", "x", "-b", "y"}, ""},
+		{"exactly one none", new(flag.Validator).ExactlyOne("a", "b"), nil, "one of the flags -a or -b is required"},
+		{"exactly one", new(flag.Validator).ExactlyOne("a", "b"), []string{"-b", "x"}, ""},
+		{"exactly one both", new(flag.Validator).ExactlyOne("a", "b"), []string{"-a", "x", "-b", "y"}, "flags -a and -b are mutually exclusive, but -a and -b were given"},
+		{"requires unset", new(flag.Validator).Requires("a", "b", "c"), []string{"-b", "x"}, ""},
+		{"requires satisfied", new(flag.Validator).Requires("a", "b", "c"), []string{"-a", "x", "-b", "y", "-c", "z"}, ""},
+		{"requires missing", new(flag.Validator).Requires("a", "b", "c"), []string{"-a", "x", "-c", "z"}, "flag -a requires -b"},
+		{"requires all missing", new(flag.Validator).Requires("a", "b", "c"), []string{"-a", "x"}, "flag -a requires -b and -c"},
+		{"undefined", new(flag.Validator).Required("a", "missing").MutuallyExclusive("missing", "b"), []string{"-a", "x"}, "flag -missing is not defined"},
+		{
+			"all violations",
+			new(flag.Validator).Required("c").MutuallyExclusive("a", "b").Requires("b", "c"),
+			[]string{"-a", "x", "-b", "y"},
+			"flag -c is required; flags -a and -b are mutually exclusive, but -a and -b were given; flag -b requires -c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+			fs.String("a", "", "")
+			fs.String("b", "", "")
+			fs.String("c", "", "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			err := tt.validator.Validate(fs)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Fatalf("Validate() = %v, want %q", err, tt.want)
+			}
+			var errs flag.ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %T, want ValidationErrors", err)
+			}
+		})
+	}
+}
+
+func TestValidatorHasChanged(t *testing.T) {
+	fs := goflag.NewFlagSet("test", goflag.ContinueOnError)
+	mode := &constflag.Enum{Choices: []string{"fast", "slow"}}
+	fs.Var(mode, "mode", "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	v := new(flag.Validator).Required("mode")
+	if err := v.Validate(fs); err == nil {
+		t.Fatal("Validate() = nil before the value was set")
+	}
+	// Values set outside the flag set, e.g. from the environment, count as set.
+	if err := mode.Set("fast"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(fs); err != nil {
+		t.Errorf("Validate() = %v after the value was set", err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	errs := flag.ValidationErrors{errors.New("first"), errors.New("second"), errors.New("third")}
+	if got, want := errs.Error(), "first; second; third"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := (flag.ValidationErrors{errors.New("only")}).Error(); got != "only" {
+		t.Errorf("Error() = %q, want %q", got, "only")
+	}
+}
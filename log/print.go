@@ -8,6 +8,8 @@ import (
 	"io"
 	"sync"
 	"github.com/pkg/errors"
+
+	"github.com/gofunct/functional/flag/driver"
 )
 
 var cyan func(string) string
@@ -59,13 +61,13 @@ func init() {
 // Debug writes a debug statement to stdout.
 func Debug(group string, format string, any ...interface{}) {
 	_, _ =  fmt.Fprint(LogWriter, gray(group)+" ")
-	_, _ = fmt.Fprintf(LogWriter, gray(format))
+	_, _ = fmt.Fprintf(LogWriter, gray(format), redact(any)...)
 }
 
 // Info writes an info statement to stdout.
 func Info(group string, format string, any ...interface{}) {
 	_, _ = fmt.Fprint(LogWriter, cyan(group)+" ")
-	_, _ = fmt.Fprintf(LogWriter, format, any...)
+	_, _ = fmt.Fprintf(LogWriter, format, redact(any)...)
 }
 
 // InfoColorful writes an info statement to stdout changing colors
@@ -76,24 +78,24 @@ func InfoColorful(group string, format string, any ...interface{}) {
 	colorFn := colorfulFormats[colorfulMap[group]%len(colorfulFormats)]
 	colorfulMutex.Unlock()
 	_, _ = fmt.Fprint(LogWriter, cyan(group)+" ")
-	s := colorFn(fmt.Sprintf(format, any...))
+	s := colorFn(fmt.Sprintf(format, redact(any)...))
 
 	_, _ =  fmt.Fprint(LogWriter, s)
 }
 
 // Error writes an error statement to stdout.
 func Error(group string, format string, any ...interface{}) error {
-	_, _ =fmt.Fprintf(LogWriter, red(group)+" ")
-	_ , _ = fmt.Fprintf(LogWriter, red(format), any...)
+	_, _ =fmt.Fprint(LogWriter, red(group)+" ")
+	_ , _ = fmt.Fprintf(LogWriter, red(format), redact(any)...)
 
-	return fmt.Errorf(format, any...)
+	return fmt.Errorf(format, redact(any)...)
 }
 
 // Panic writes an error statement to stdout.
 func Panic(group string, format string, any ...interface{}) {
-	_, _ = fmt.Fprintf(LogWriter, redInverse(group)+" ")
+	_, _ = fmt.Fprint(LogWriter, redInverse(group)+" ")
 
-	_, _ = fmt.Fprintf(LogWriter, redInverse(format), any...)
+	_, _ = fmt.Fprintf(LogWriter, redInverse(format), redact(any)...)
 
 	panic("")
 }
@@ -106,4 +108,18 @@ func WithStack(e error) error {
 // Error writes an error statement to stdout.
 func Hint(e error, hint string) error {
 	return errors.Wrap(e, hint)
-}
\ No newline at end of file
+}
+
+// redact replaces the arguments holding secrets, such as `typeflag.Secret` flag values,
+// with their redacted text.
+func redact(any []interface{}) []interface{} {
+	out := make([]interface{}, len(any))
+	for i, v := range any {
+		if s, ok := v.(driver.Sensitive); ok {
+			out[i] = s.Redacted()
+			continue
+		}
+		out[i] = v
+	}
+	return out
+}
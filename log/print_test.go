@@ -0,0 +1,47 @@
+package print_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gofunct/functional/flag/typeflag"
+	print "github.com/gofunct/functional/log"
+)
+
+func TestRedact(t *testing.T) {
+	secret := &typeflag.Secret{}
+	if err := secret.Set("hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	writer := print.LogWriter.Writer
+	print.LogWriter.Writer = &out
+	defer func() { print.LogWriter.Writer = writer }()
+
+	tests := []struct {
+		name string
+		log  func() error
+	}{
+		{"Debug", func() error { print.Debug("auth", "token %s for %s\n", secret, "alice"); return nil }},
+		{"Info", func() error { print.Info("auth", "token %s for %s\n", secret, "alice"); return nil }},
+		{"InfoColorful", func() error { print.InfoColorful("auth", "token %v for %s\n", secret, "alice"); return nil }},
+		{"Error", func() error { return print.Error("auth", "token %q for %s\n", secret, "alice") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out.Reset()
+			err := tt.log()
+			logged := out.String()
+			if err != nil {
+				logged += err.Error()
+			}
+			if strings.Contains(logged, "hunter2") {
+				t.Errorf("logged the secret: %q", logged)
+			}
+			if !strings.Contains(logged, typeflag.RedactedText) || !strings.Contains(logged, "alice") {
+				t.Errorf("log = %q, want the redacted text and the other arguments", logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+// Authentication schemes reported in Principal.Scheme.
+const (
+	AuthSchemeBasic  = "basic"
+	AuthSchemeBearer = "bearer"
+	AuthSchemeHMAC   = "hmac"
+	AuthSchemeJWT    = "jwt"
+)
+
+// ErrInvalidCredentials is returned by validators for credentials that are not accepted.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Principal is the authenticated identity of a request, placed in the request
+// context by the authentication middleware.
+type Principal struct {
+	Name   string
+	Scheme string
+	Claims map[string]interface{}
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal stored by an authentication middleware.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// WithPrincipal returns a copy of ctx carrying p, for custom authentication middleware.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// BasicAuth returns middleware that requires HTTP Basic authentication with a user name
+// and password accepted by validate, answering other requests with 401 Unauthorized and
+// a challenge for realm. The authenticated user is stored as the Principal of the request.
+//
+// Validators should compare credentials in constant time, as BasicAuthUsers and
+// Htpasswd.Validate do.
+//
+// Example:
+//
+//  users, err := handlers.LoadHtpasswdFile("/etc/app/htpasswd")
+//  if err != nil {
+//  	log.Fatal(err)
+//  }
+//  http.ListenAndServe(":1123", handlers.BasicAuth("admin", users.Validate)(r))
+func BasicAuth(realm string, validate func(user, password string) bool) Middleware {
+	challenge := fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, realm)
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			if !ok || !validate(user, password) {
+				w.Header().Set("WWW-Authenticate", challenge)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			p := &Principal{Name: user, Scheme: AuthSchemeBasic}
+			h.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// BasicAuthUsers returns a validator for BasicAuth accepting the given users and plain-text
+// passwords. Passwords are compared in constant time.
+func BasicAuthUsers(users map[string]string) func(user, password string) bool {
+	digests := make(map[string]string, len(users))
+	for user, password := range users {
+		digests[user] = crypto.Sha256sum(password)
+	}
+	return func(user, password string) bool {
+		digest, ok := digests[user]
+		if !ok {
+			// Compare anyway, so that unknown users take as long as wrong passwords.
+			digest = crypto.Sha256sum("")
+		}
+		match := subtle.ConstantTimeCompare([]byte(digest), []byte(crypto.Sha256sum(password))) == 1
+		return ok && match
+	}
+}
+
+// TokenValidator validates a bearer token and returns the principal it identifies,
+// or an error such as ErrInvalidCredentials.
+type TokenValidator func(ctx context.Context, token string) (*Principal, error)
+
+// BearerAuth returns middleware that requires an RFC 6750 bearer token in the Authorization
+// header accepted by validate, answering other requests with 401 Unauthorized.
+// The principal returned by validate is stored in the request context.
+func BearerAuth(validate TokenValidator) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			p, err := validate(r.Context(), token)
+			if err != nil || p == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			if p.Scheme == "" {
+				p.Scheme = AuthSchemeBearer
+			}
+			h.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// StaticTokens returns a TokenValidator accepting the given tokens, mapped to the names
+// of their principals. Tokens are compared in constant time.
+func StaticTokens(tokens map[string]string) TokenValidator {
+	type entry struct {
+		digest []byte
+		name   string
+	}
+	entries := make([]entry, 0, len(tokens))
+	for token, name := range tokens {
+		entries = append(entries, entry{[]byte(crypto.Sha256sum(token)), name})
+	}
+	return func(_ context.Context, token string) (*Principal, error) {
+		digest := []byte(crypto.Sha256sum(token))
+		var found *Principal
+		for _, e := range entries {
+			if subtle.ConstantTimeCompare(e.digest, digest) == 1 {
+				found = &Principal{Name: e.name, Scheme: AuthSchemeBearer}
+			}
+		}
+		if found == nil {
+			return nil, ErrInvalidCredentials
+		}
+		return found, nil
+	}
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	const prefix = "bearer "
+	if len(auth) <= len(prefix) || !strings.EqualFold(auth[:len(prefix)], prefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(auth[len(prefix):])
+	return token, token != ""
+}
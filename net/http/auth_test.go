@@ -0,0 +1,121 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestBasicAuth(t *testing.T) {
+	validate := handlers.BasicAuthUsers(map[string]string{"alice": "secret"})
+	tests := []struct {
+		name      string
+		user      string
+		password  string
+		noAuth    bool
+		wantCode  int
+		wantPrinc string
+	}{
+		{name: "valid", user: "alice", password: "secret", wantCode: http.StatusOK, wantPrinc: "alice"},
+		{name: "wrong password", user: "alice", password: "nope", wantCode: http.StatusUnauthorized},
+		{name: "unknown user", user: "bob", password: "secret", wantCode: http.StatusUnauthorized},
+		{name: "unknown user empty password", user: "bob", password: "", wantCode: http.StatusUnauthorized},
+		{name: "no credentials", noAuth: true, wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *handlers.Principal
+			h := handlers.BasicAuth("test", validate)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = handlers.PrincipalFromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if !tt.noAuth {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") != `Basic realm="test", charset="UTF-8"` {
+					t.Errorf("WWW-Authenticate = %q", rec.Header().Get("WWW-Authenticate"))
+				}
+				return
+			}
+			if got == nil || got.Name != tt.wantPrinc || got.Scheme != handlers.AuthSchemeBasic {
+				t.Errorf("principal = %+v, want %s", got, tt.wantPrinc)
+			}
+		})
+	}
+}
+
+func TestBearerAuth(t *testing.T) {
+	validate := handlers.StaticTokens(map[string]string{"t0ken": "ci"})
+	tests := []struct {
+		name      string
+		auth      string
+		wantCode  int
+		wantChall string
+		wantPrinc string
+	}{
+		{name: "valid", auth: "Bearer t0ken", wantCode: http.StatusOK, wantPrinc: "ci"},
+		{name: "case-insensitive scheme", auth: "bearer t0ken", wantCode: http.StatusOK, wantPrinc: "ci"},
+		{name: "invalid token", auth: "Bearer other", wantCode: http.StatusUnauthorized, wantChall: `Bearer error="invalid_token"`},
+		{name: "missing token", auth: "Bearer ", wantCode: http.StatusUnauthorized, wantChall: "Bearer"},
+		{name: "other scheme", auth: "Basic dDBrZW4=", wantCode: http.StatusUnauthorized, wantChall: "Bearer"},
+		{name: "no header", wantCode: http.StatusUnauthorized, wantChall: "Bearer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *handlers.Principal
+			h := handlers.BearerAuth(validate)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = handlers.PrincipalFromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantChall != "" && rec.Header().Get("WWW-Authenticate") != tt.wantChall {
+				t.Errorf("WWW-Authenticate = %q, want %q", rec.Header().Get("WWW-Authenticate"), tt.wantChall)
+			}
+			if tt.wantPrinc != "" && (got == nil || got.Name != tt.wantPrinc || got.Scheme != handlers.AuthSchemeBearer) {
+				t.Errorf("principal = %+v, want %s", got, tt.wantPrinc)
+			}
+		})
+	}
+}
+
+func TestStaticTokens(t *testing.T) {
+	validate := handlers.StaticTokens(map[string]string{"a": "first", "b": "second"})
+	tests := []struct {
+		token   string
+		want    string
+		wantErr error
+	}{
+		{"a", "first", nil},
+		{"b", "second", nil},
+		{"c", "", handlers.ErrInvalidCredentials},
+		{"", "", handlers.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		p, err := validate(context.Background(), tt.token)
+		if err != tt.wantErr {
+			t.Errorf("%q: err = %v, want %v", tt.token, err, tt.wantErr)
+			continue
+		}
+		if err == nil && p.Name != tt.want {
+			t.Errorf("%q: name = %q, want %q", tt.token, p.Name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Middleware wraps an http.Handler with additional behavior.
+//
+// CORS, CanonicalHost and RecoveryHandler return a Middleware, and
+// CompressHandler, ProxyHeaders and HTTPMethodOverrideHandler are one.
+// Logging, CombinedLogging, CustomLogging, CompressLevel and ContentTypes
+// adapt the handlers taking further arguments.
+type Middleware func(http.Handler) http.Handler
+
+// Chain is an ordered list of middleware applied to a handler.
+// The first middleware is the outermost one and sees each request first.
+//
+// A Chain is immutable: Append, Use and Extend return new chains and never
+// modify the chain they are called on, so a common base chain can be shared.
+//
+// Example:
+//
+//  base := handlers.NewChain(handlers.RecoveryHandler(), handlers.Logging(os.Stdout))
+//  api := base.Use("cors", handlers.CORS()).Append(handlers.ContentTypes("application/json"))
+//
+//  http.Handle("/", base.Then(site))
+//  http.Handle("/api/", api.Then(apiHandler))
+type Chain struct {
+	links []link
+}
+
+type link struct {
+	name       string
+	middleware Middleware
+}
+
+// NewChain returns a chain of the given middleware.
+func NewChain(middleware ...Middleware) Chain {
+	return Chain{}.Append(middleware...)
+}
+
+// Append returns a new chain with the given middleware added after the existing ones.
+func (c Chain) Append(middleware ...Middleware) Chain {
+	links := make([]link, 0, len(c.links)+len(middleware))
+	links = append(links, c.links...)
+	for _, m := range middleware {
+		links = append(links, link{middleware: m})
+	}
+	return Chain{links: links}
+}
+
+// Use returns a new chain with the middleware added under name, which is reported by Names.
+func (c Chain) Use(name string, m Middleware) Chain {
+	links := make([]link, 0, len(c.links)+1)
+	links = append(links, c.links...)
+	return Chain{links: append(links, link{name: name, middleware: m})}
+}
+
+// Extend returns a new chain with the middleware of other added after the existing ones.
+func (c Chain) Extend(other Chain) Chain {
+	links := make([]link, 0, len(c.links)+len(other.links))
+	links = append(links, c.links...)
+	return Chain{links: append(links, other.links...)}
+}
+
+// Then returns h wrapped by the middleware of the chain.
+// A nil h is replaced by http.DefaultServeMux.
+func (c Chain) Then(h http.Handler) http.Handler {
+	if h == nil {
+		h = http.DefaultServeMux
+	}
+	for i := len(c.links) - 1; i >= 0; i-- {
+		if c.links[i].middleware != nil {
+			h = c.links[i].middleware(h)
+		}
+	}
+	return h
+}
+
+// ThenFunc works like Then for an http.HandlerFunc.
+func (c Chain) ThenFunc(fn http.HandlerFunc) http.Handler {
+	if fn == nil {
+		return c.Then(nil)
+	}
+	return c.Then(fn)
+}
+
+// Len returns the number of middleware in the chain.
+func (c Chain) Len() int {
+	return len(c.links)
+}
+
+// Names returns the names of the middleware in the order they see requests.
+// Middleware added without a name are reported by the name of their function.
+func (c Chain) Names() []string {
+	names := make([]string, len(c.links))
+	for i, l := range c.links {
+		names[i] = l.name
+		if names[i] == "" {
+			names[i] = funcName(l.middleware)
+		}
+	}
+	return names
+}
+
+// String returns the names of the middleware joined by arrows, for debugging.
+func (c Chain) String() string {
+	return strings.Join(append(c.Names(), "handler"), " -> ")
+}
+
+// funcName returns the unqualified name of the function m, or of the function that returned
+// it if m is a closure, e.g. "CompressHandler" or "CORS".
+func funcName(m Middleware) string {
+	if m == nil {
+		return "<nil>"
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(m).Pointer())
+	if fn == nil {
+		return "<unknown>"
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// When returns middleware that applies m to the requests for which cond returns true,
+// and passes all other requests directly to the wrapped handler.
+//
+// Example:
+//
+//  chain := handlers.NewChain(handlers.When(handlers.PathPrefix("/api/"), handlers.CORS()))
+func When(cond func(*http.Request) bool, m Middleware) Middleware {
+	return func(h http.Handler) http.Handler {
+		wrapped := m(h)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cond(r) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Unless returns middleware that applies m to the requests for which cond returns false.
+func Unless(cond func(*http.Request) bool, m Middleware) Middleware {
+	return When(func(r *http.Request) bool { return !cond(r) }, m)
+}
+
+// PathPrefix returns a condition for When that matches requests whose URL path starts with prefix.
+func PathPrefix(prefix string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		return strings.HasPrefix(r.URL.Path, prefix)
+	}
+}
+
+// Methods returns a condition for When that matches requests with one of the given methods.
+func Methods(methods ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		for _, m := range methods {
+			if strings.EqualFold(r.Method, m) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Logging returns middleware that logs requests to out like LoggingHandler.
+func Logging(out io.Writer) Middleware {
+	return func(h http.Handler) http.Handler {
+		return LoggingHandler(out, h)
+	}
+}
+
+// CombinedLogging returns middleware that logs requests to out like CombinedLoggingHandler.
+func CombinedLogging(out io.Writer) Middleware {
+	return func(h http.Handler) http.Handler {
+		return CombinedLoggingHandler(out, h)
+	}
+}
+
+// CustomLogging returns middleware that logs requests to out using f like CustomLoggingHandler.
+func CustomLogging(out io.Writer, f LogFormatter) Middleware {
+	return func(h http.Handler) http.Handler {
+		return CustomLoggingHandler(out, h, f)
+	}
+}
+
+// CompressLevel returns middleware that compresses responses like CompressHandlerLevel.
+func CompressLevel(level int) Middleware {
+	return func(h http.Handler) http.Handler {
+		return CompressHandlerLevel(h, level)
+	}
+}
+
+// ContentTypes returns middleware that validates request content types like ContentTypeHandler.
+func ContentTypes(contentTypes ...string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return ContentTypeHandler(h, contentTypes...)
+	}
+}
@@ -0,0 +1,114 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+// tag returns middleware that appends name to the X-Trace response header
+// before and after calling the wrapped handler.
+func tag(name string) handlers.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", name)
+			h.ServeHTTP(w, r)
+			w.Header().Add("X-Trace", "/"+name)
+		})
+	}
+}
+
+// trace serves a request with c and returns the X-Trace values it recorded.
+func trace(c handlers.Chain, method, path string) string {
+	h := c.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Trace", "handler")
+	})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return strings.Join(rec.Header().Values("X-Trace"), " ")
+}
+
+func TestChainOrder(t *testing.T) {
+	c := handlers.NewChain(tag("a"), tag("b"), nil).Append(tag("c"))
+	if got, want := trace(c, "GET", "/"), "a b c handler /c /b /a"; got != want {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+	if c.Len() != 4 {
+		t.Errorf("Len = %d, want 4", c.Len())
+	}
+	if got := trace(handlers.NewChain(), "GET", "/"); got != "handler" {
+		t.Errorf("empty chain trace = %q, want handler", got)
+	}
+}
+
+func TestChainImmutable(t *testing.T) {
+	base := handlers.NewChain(tag("base"))
+	withA := base.Append(tag("a"))
+	withAB := withA.Append(tag("b"))
+	withAC := withA.Append(tag("c"))
+	used := withA.Use("d", tag("d"))
+	extended := withA.Extend(handlers.NewChain(tag("e")))
+	other := withA.Extend(handlers.NewChain(tag("f")))
+
+	tests := []struct {
+		chain handlers.Chain
+		want  string
+	}{
+		{base, "base handler /base"},
+		{withA, "base a handler /a /base"},
+		{withAB, "base a b handler /b /a /base"},
+		{withAC, "base a c handler /c /a /base"},
+		{used, "base a d handler /d /a /base"},
+		{extended, "base a e handler /e /a /base"},
+		{other, "base a f handler /f /a /base"},
+	}
+	for _, tt := range tests {
+		if got := trace(tt.chain, "GET", "/"); got != tt.want {
+			t.Errorf("trace = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestChainNames(t *testing.T) {
+	c := handlers.NewChain(handlers.CompressHandler, handlers.RecoveryHandler(), tag("a"), nil).
+		Use("cors", handlers.CORS())
+	want := []string{"CompressHandler", "RecoveryHandler", "tag", "<nil>", "cors"}
+	if got := c.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names = %q, want %q", got, want)
+	}
+	if got, want := c.String(), "CompressHandler -> RecoveryHandler -> tag -> <nil> -> cors -> handler"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+	if got := handlers.NewChain().String(); got != "handler" {
+		t.Errorf("empty String = %q, want handler", got)
+	}
+}
+
+func TestChainConditions(t *testing.T) {
+	tests := []struct {
+		name   string
+		m      handlers.Middleware
+		method string
+		path   string
+		want   string
+	}{
+		{"when matching", handlers.When(handlers.PathPrefix("/api/"), tag("a")), "GET", "/api/items", "a handler /a"},
+		{"when not matching", handlers.When(handlers.PathPrefix("/api/"), tag("a")), "GET", "/apis", "handler"},
+		{"unless matching", handlers.Unless(handlers.PathPrefix("/api/"), tag("a")), "GET", "/api/items", "handler"},
+		{"unless not matching", handlers.Unless(handlers.PathPrefix("/api/"), tag("a")), "GET", "/", "a handler /a"},
+		{"methods", handlers.When(handlers.Methods("POST", "put"), tag("a")), "PUT", "/", "a handler /a"},
+		{"other method", handlers.When(handlers.Methods("POST", "put"), tag("a")), "GET", "/", "handler"},
+		{"no methods", handlers.When(handlers.Methods(), tag("a")), "GET", "/", "handler"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trace(handlers.NewChain(tt.m), tt.method, tt.path); got != tt.want {
+				t.Errorf("trace = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
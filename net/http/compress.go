@@ -1,57 +1,315 @@
 package http
 
 import (
+	"bufio"
 	"compress/flate"
 	"compress/gzip"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings supported by the compression handlers.
+const (
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
 )
 
+// defaultEncodings lists the supported encodings in order of preference.
+var defaultEncodings = []string{EncodingBrotli, EncodingZstd, EncodingGzip, EncodingDeflate}
+
+// compressedContentTypes are never compressed again, unless allowed explicitly
+// with CompressionContentTypes. Types ending in "/" match all subtypes.
+var compressedContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"font/woff2",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-bzip2",
+	"application/x-xz",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/zstd",
+	"application/vnd.rar",
+}
+
+// compressWriter is implemented by all encoders.
+type compressWriter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// CompressOption configures the Compress middleware.
+type CompressOption func(*compressor)
+
+type compressor struct {
+	level        int
+	encodings    []string
+	minSize      int
+	contentTypes []string
+	// encoders pools the encoders of each encoding, which are costly to create.
+	encoders map[string]*sync.Pool
+}
+
+// compressResponseWriter decides whether to compress once the content type
+// and the first minSize bytes of the response are known, buffering until then.
 type compressResponseWriter struct {
-	io.Writer
 	http.ResponseWriter
-	http.Hijacker
-	http.Flusher
-	http.CloseNotifier
-}
 
-func (w *compressResponseWriter) WriteHeader(c int) {
-	w.ResponseWriter.Header().Del("Content-Length")
-	w.ResponseWriter.WriteHeader(c)
+	c        *compressor
+	encoding string
+	status   int
+	buf      []byte
+	decided  bool
+	encoder  compressWriter
 }
 
-func (w *compressResponseWriter) Header() http.Header {
-	return w.ResponseWriter.Header()
+func (w *compressResponseWriter) WriteHeader(c int) {
+	if w.decided || c < http.StatusOK {
+		w.ResponseWriter.WriteHeader(c)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	w.status = c
+	if c == http.StatusNoContent || c == http.StatusNotModified {
+		w.decide(false)
+	}
 }
 
 func (w *compressResponseWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
 	h := w.ResponseWriter.Header()
 	if h.Get("Content-Type") == "" {
-		h.Set("Content-Type", http.DetectContentType(b))
+		h.Set("Content-Type", http.DetectContentType(append(w.buf, b...)))
 	}
-	h.Del("Content-Length")
-
-	return w.Writer.Write(b)
-}
-
-type flusher interface {
-	Flush() error
+	if !w.compressible() {
+		w.decide(false)
+		return w.Write(b)
+	}
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.c.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
 }
 
 func (w *compressResponseWriter) Flush() {
+	if !w.decided {
+		w.decide(w.ResponseWriter.Header().Get("Content-Type") != "" && w.compressible())
+	}
 	// Flush compressed data if compressor supports it.
-	if f, ok := w.Writer.(flusher); ok {
-		f.Flush()
+	if w.encoder != nil {
+		w.encoder.Flush()
 	}
 	// Flush HTTP response.
-	if w.Flusher != nil {
-		w.Flusher.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
 	}
 }
 
-// CompressHandler gzip compresses HTTP responses for clients that support it
-// via the 'Accept-Encoding' header.
+func (w *compressResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("compressResponseWriter does not implement http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+func (w *compressResponseWriter) CloseNotify() <-chan bool {
+	if cn, ok := w.ResponseWriter.(http.CloseNotifier); ok {
+		return cn.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// close writes out a response that never reached the minimum size and closes the encoder.
+func (w *compressResponseWriter) close() error {
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.encoder == nil {
+		return nil
+	}
+	err := w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	w.c.encoders[w.encoding].Put(w.encoder)
+	w.encoder = nil
+	return err
+}
+
+// compressible returns true if the response headers allow compressing the response.
+func (w *compressResponseWriter) compressible() bool {
+	h := w.ResponseWriter.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" {
+		return false
+	}
+	ct := h.Get("Content-Type")
+	if i := strings.IndexByte(ct, ';'); i >= 0 {
+		ct = ct[:i]
+	}
+	ct = strings.ToLower(strings.TrimSpace(ct))
+	if len(w.c.contentTypes) > 0 {
+		return matchContentType(ct, w.c.contentTypes)
+	}
+	return ct == "image/svg+xml" || !matchContentType(ct, compressedContentTypes)
+}
+
+// decide sends the headers, with compression if compress is set, and the buffered data.
+func (w *compressResponseWriter) decide(compress bool) error {
+	w.decided = true
+	h := w.ResponseWriter.Header()
+	if compress {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		w.encoder = w.c.encoders[w.encoding].Get().(compressWriter)
+		w.encoder.Reset(w.ResponseWriter)
+	}
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func matchContentType(ct string, types []string) bool {
+	for _, t := range types {
+		t = strings.ToLower(t)
+		if ct == t || strings.HasSuffix(t, "/") && strings.HasPrefix(ct, t) || strings.HasSuffix(t, "/*") && strings.HasPrefix(ct, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// newEncoder returns an encoder writing to w, to be reset to the response writer before use.
+func newEncoder(encoding string, w io.Writer, level int) compressWriter {
+	switch encoding {
+	case EncodingBrotli:
+		if level == gzip.DefaultCompression {
+			level = brotli.DefaultCompression
+		}
+		return brotli.NewWriterLevel(w, level)
+	case EncodingZstd:
+		zlevel := zstd.SpeedDefault
+		if level != gzip.DefaultCompression {
+			zlevel = zstd.EncoderLevelFromZstd(level)
+		}
+		zw, _ := zstd.NewWriter(w, zstd.WithEncoderLevel(zlevel), zstd.WithEncoderConcurrency(1))
+		return zw
+	case EncodingDeflate:
+		fw, _ := flate.NewWriter(w, level)
+		return fw
+	}
+	gw, _ := gzip.NewWriterLevel(w, level)
+	return gw
+}
+
+// negotiateEncoding picks the encoding with the highest q-value in the Accept-Encoding
+// header that is also in encodings, preferring earlier encodings on ties.
+// It returns "" if the response should not be encoded.
+func negotiateEncoding(acceptEncoding string, encodings []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	qs := make(map[string]float64)
+	wildcard := -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, q := parseQuality(part)
+		if coding == "" {
+			continue
+		}
+		if coding == "*" {
+			wildcard = q
+			continue
+		}
+		qs[coding] = q
+	}
+	if q, ok := qs["x-gzip"]; ok {
+		if _, ok := qs[EncodingGzip]; !ok {
+			qs[EncodingGzip] = q
+		}
+	}
+	type candidate struct {
+		encoding string
+		q        float64
+	}
+	var candidates []candidate
+	for _, enc := range encodings {
+		q, ok := qs[enc]
+		if !ok {
+			q = wildcard
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{enc, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].encoding
+}
+
+// parseQuality splits an Accept-Encoding element into its lower-cased coding and q-value.
+func parseQuality(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	coding := strings.ToLower(strings.TrimSpace(params[0]))
+	q := 1.0
+	for _, p := range params[1:] {
+		p = strings.TrimSpace(p)
+		if !strings.HasPrefix(strings.ToLower(p), "q=") {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(p[2:]), 64)
+		if err != nil || v < 0 || v > 1 {
+			return coding, 0
+		}
+		q = v
+	}
+	return coding, q
+}
+
+// CompressHandler compresses HTTP responses for clients that support it
+// via the 'Accept-Encoding' header, using brotli, zstd, gzip or deflate.
 //
 // Compressing TLS traffic may leak the page contents to an attacker if the
 // page contains user input: http://security.stackexchange.com/a/102015/12208
@@ -59,86 +317,111 @@ func CompressHandler(h http.Handler) http.Handler {
 	return CompressHandlerLevel(h, gzip.DefaultCompression)
 }
 
-// CompressHandlerLevel gzip compresses HTTP responses with specified compression level
+// CompressHandlerLevel compresses HTTP responses with specified compression level
 // for clients that support it via the 'Accept-Encoding' header.
 //
 // The compression level should be gzip.DefaultCompression, gzip.NoCompression,
 // or any integer value between gzip.BestSpeed and gzip.BestCompression inclusive.
 // gzip.DefaultCompression is used in case of invalid compression level.
 func CompressHandlerLevel(h http.Handler, level int) http.Handler {
-	if level < gzip.DefaultCompression || level > gzip.BestCompression {
-		level = gzip.DefaultCompression
-	}
-
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-	L:
-		for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
-			switch strings.TrimSpace(enc) {
-			case "gzip":
-				w.Header().Set("Content-Encoding", "gzip")
-				w.Header().Add("Vary", "Accept-Encoding")
-
-				gw, _ := gzip.NewWriterLevel(w, level)
-				defer gw.Close()
-
-				h, hok := w.(http.Hijacker)
-				if !hok { /* w is not Hijacker... oh well... */
-					h = nil
-				}
-
-				f, fok := w.(http.Flusher)
-				if !fok {
-					f = nil
-				}
-
-				cn, cnok := w.(http.CloseNotifier)
-				if !cnok {
-					cn = nil
-				}
-
-				w = &compressResponseWriter{
-					Writer:         gw,
-					ResponseWriter: w,
-					Hijacker:       h,
-					Flusher:        f,
-					CloseNotifier:  cn,
-				}
+	return Compress(CompressionLevel(level))(h)
+}
 
-				break L
-			case "deflate":
-				w.Header().Set("Content-Encoding", "deflate")
-				w.Header().Add("Vary", "Accept-Encoding")
+// Compress returns middleware that compresses HTTP responses for clients that
+// support it via the 'Accept-Encoding' header.
+//
+// The encoding is negotiated using the q-values of the header, preferring brotli,
+// zstd, gzip and deflate in that order on ties. Responses that already have a
+// Content-Encoding, partial content, and types that are already compressed, such as
+// images other than SVG, video, audio and archives, are sent unchanged.
+//
+// Example:
+//
+//  compress := handlers.Compress(
+//  	handlers.CompressionMinSize(1024),
+//  	handlers.CompressionContentTypes("text/", "application/json"))
+//  http.ListenAndServe(":1123", compress(r))
+func Compress(opts ...CompressOption) Middleware {
+	c := &compressor{
+		level:     gzip.DefaultCompression,
+		encodings: defaultEncodings,
+	}
+	for _, option := range opts {
+		option(c)
+	}
+	c.encoders = make(map[string]*sync.Pool, len(c.encodings))
+	for _, encoding := range c.encodings {
+		encoding := encoding
+		c.encoders[encoding] = &sync.Pool{New: func() interface{} {
+			return newEncoder(encoding, io.Discard, c.level)
+		}}
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), c.encodings)
+			if encoding == "" || r.Method == http.MethodHead {
+				h.ServeHTTP(w, r)
+				return
+			}
 
-				fw, _ := flate.NewWriter(w, level)
-				defer fw.Close()
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				c:              c,
+				encoding:       encoding,
+			}
+			defer cw.close()
 
-				h, hok := w.(http.Hijacker)
-				if !hok { /* w is not Hijacker... oh well... */
-					h = nil
-				}
+			h.ServeHTTP(cw, r)
+		})
+	}
+}
 
-				f, fok := w.(http.Flusher)
-				if !fok {
-					f = nil
-				}
+//
+// Functional options for configuring Compress.
+//
 
-				cn, cnok := w.(http.CloseNotifier)
-				if !cnok {
-					cn = nil
-				}
+// CompressionLevel sets the compression level, on the scale of gzip.BestSpeed to
+// gzip.BestCompression. It is passed to brotli as is and converted for zstd.
+// gzip.DefaultCompression selects the default level of each encoding, and is used
+// in case of an invalid level.
+func CompressionLevel(level int) CompressOption {
+	return func(c *compressor) {
+		if level < gzip.DefaultCompression || level > gzip.BestCompression {
+			level = gzip.DefaultCompression
+		}
+		c.level = level
+	}
+}
 
-				w = &compressResponseWriter{
-					Writer:         fw,
-					ResponseWriter: w,
-					Hijacker:       h,
-					Flusher:        f,
-					CloseNotifier:  cn,
+// CompressionEncodings restricts the encodings offered to the given ones, in order
+// of preference. Unsupported encodings are ignored.
+func CompressionEncodings(encodings ...string) CompressOption {
+	return func(c *compressor) {
+		c.encodings = nil
+		for _, enc := range encodings {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			for _, supported := range defaultEncodings {
+				if enc == supported {
+					c.encodings = append(c.encodings, enc)
 				}
-
-				break L
 			}
 		}
+	}
+}
 
-		h.ServeHTTP(w, r)
-	})
+// CompressionMinSize leaves responses shorter than size bytes uncompressed.
+// Responses are buffered until size bytes are written or the handler flushes.
+func CompressionMinSize(size int) CompressOption {
+	return func(c *compressor) {
+		c.minSize = size
+	}
+}
+
+// CompressionContentTypes compresses only responses of the given content types.
+// A type ending in "/" or "/*", such as "text/", matches all its subtypes.
+func CompressionContentTypes(types ...string) CompressOption {
+	return func(c *compressor) {
+		c.contentTypes = types
+	}
 }
@@ -0,0 +1,276 @@
+package http_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+// decode undoes the content coding of a response body.
+func decode(t *testing.T, coding string, b []byte) string {
+	t.Helper()
+	var r io.Reader
+	var err error
+	switch coding {
+	case "":
+		r = bytes.NewReader(b)
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(b))
+	case "deflate":
+		r = flate.NewReader(bytes.NewReader(b))
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(b))
+	case "zstd":
+		var zr *zstd.Decoder
+		zr, err = zstd.NewReader(bytes.NewReader(b))
+		if err == nil {
+			defer zr.Close()
+		}
+		r = zr
+	default:
+		t.Fatalf("unknown coding %q", coding)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", coding, err)
+	}
+	return string(out)
+}
+
+func TestCompressNegotiation(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"GZIP;Q=1", "gzip"},
+		{"x-gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, br", "br"},
+		{"gzip;q=0", ""},
+		{"gzip;q=0.5, br;q=0.5", "br"},
+		{"gzip;q=0.5, br;q=0.4", "gzip"},
+		{"zstd;q=0.9, gzip", "gzip"},
+		{"*", "br"},
+		{"*;q=0", ""},
+		{"br;q=0, *", "zstd"},
+		{"gzip, *;q=0", "gzip"},
+		{"identity", ""},
+		{"gzip;q=abc", ""},
+		{"gzip;q=2", ""},
+		{"compress", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			h := handlers.Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "hello")
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+			if body := decode(t, tt.want, rec.Body.Bytes()); body != "hello" {
+				t.Errorf("body = %q, want hello", body)
+			}
+		})
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("compressible text ", 100)
+	tests := []struct {
+		name        string
+		opts        []handlers.CompressOption
+		method      string
+		handler     func(w http.ResponseWriter)
+		wantCoding  string
+		wantBody    string
+		wantLength  string
+		wantFlushed bool
+	}{
+		{
+			name:       "html",
+			handler:    func(w http.ResponseWriter) { io.WriteString(w, "<html>"+large) },
+			wantCoding: "gzip",
+			wantBody:   "<html>" + large,
+		},
+		{
+			name:       "below min size",
+			opts:       []handlers.CompressOption{handlers.CompressionMinSize(1024)},
+			handler:    func(w http.ResponseWriter) { io.WriteString(w, "short") },
+			wantCoding: "",
+			wantBody:   "short",
+		},
+		{
+			name: "min size reached over several writes",
+			opts: []handlers.CompressOption{handlers.CompressionMinSize(1024)},
+			handler: func(w http.ResponseWriter) {
+				io.WriteString(w, large[:1000])
+				io.WriteString(w, large[1000:])
+			},
+			wantCoding: "gzip",
+			wantBody:   large,
+		},
+		{
+			name: "flush below min size",
+			opts: []handlers.CompressOption{handlers.CompressionMinSize(1024)},
+			handler: func(w http.ResponseWriter) {
+				io.WriteString(w, "short")
+				w.(http.Flusher).Flush()
+				io.WriteString(w, " and more")
+			},
+			wantCoding:  "gzip",
+			wantBody:    "short and more",
+			wantFlushed: true,
+		},
+		{
+			name: "content length removed",
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Length", "1800")
+				io.WriteString(w, large)
+			},
+			wantCoding: "gzip",
+			wantBody:   large,
+		},
+		{
+			name: "content length kept",
+			opts: []handlers.CompressOption{handlers.CompressionMinSize(1024)},
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Length", "5")
+				io.WriteString(w, "short")
+			},
+			wantBody:   "short",
+			wantLength: "5",
+		},
+		{
+			name: "content types filter",
+			opts: []handlers.CompressOption{handlers.CompressionContentTypes("application/json")},
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/plain")
+				io.WriteString(w, large)
+			},
+			wantBody: large,
+		},
+		{
+			name: "content types match",
+			opts: []handlers.CompressOption{handlers.CompressionContentTypes("application/json", "text/*")},
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				io.WriteString(w, large)
+			},
+			wantCoding: "gzip",
+			wantBody:   large,
+		},
+		{
+			name: "compressed type",
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "image/png")
+				io.WriteString(w, large)
+			},
+			wantBody: large,
+		},
+		{
+			name: "svg",
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "image/svg+xml")
+				io.WriteString(w, large)
+			},
+			wantCoding: "gzip",
+			wantBody:   large,
+		},
+		{
+			name: "pre-encoded",
+			handler: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Encoding", "br")
+				io.WriteString(w, large)
+			},
+			wantCoding: "br",
+			wantBody:   large,
+		},
+		{
+			name:    "no content",
+			handler: func(w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) },
+		},
+		{
+			name:    "not modified",
+			handler: func(w http.ResponseWriter) { w.WriteHeader(http.StatusNotModified) },
+		},
+		{
+			name:    "head",
+			method:  "HEAD",
+			handler: func(w http.ResponseWriter) { w.Header().Set("Content-Type", "text/html") },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handlers.Compress(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.handler(w)
+			}))
+			method := tt.method
+			if method == "" {
+				method = "GET"
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantCoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantCoding)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if got := rec.Header().Get("Content-Length"); got != tt.wantLength {
+				t.Errorf("Content-Length = %q, want %q", got, tt.wantLength)
+			}
+			if rec.Flushed != tt.wantFlushed {
+				t.Errorf("Flushed = %v, want %v", rec.Flushed, tt.wantFlushed)
+			}
+			coding := tt.wantCoding
+			if coding == "br" && tt.name == "pre-encoded" {
+				coding = ""
+			}
+			if body := decode(t, coding, rec.Body.Bytes()); body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCompressEncoderReuse(t *testing.T) {
+	h := handlers.Compress()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.URL.Query().Get("body"))
+	}))
+	for _, coding := range []string{"br", "zstd", "gzip", "deflate"} {
+		for i := 0; i < 3; i++ {
+			body := strings.Repeat(coding, i+1)
+			req := httptest.NewRequest("GET", "/?body="+body, nil)
+			req.Header.Set("Accept-Encoding", coding)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if got := decode(t, coding, rec.Body.Bytes()); got != body {
+				t.Errorf("%s response %d = %q, want %q", coding, i, got, body)
+			}
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+const (
+	// CSRFHeader is the default header carrying the token of requests made by scripts.
+	CSRFHeader = "X-CSRF-Token"
+	// CSRFField is the default name of the form field carrying the token.
+	CSRFField = "csrf_token"
+	// CSRFCookie is the default name of the cookie holding the token.
+	CSRFCookie = "_csrf"
+
+	csrfTokenLength = 32
+)
+
+// Reasons for rejecting a request, available from CSRFFailureReason.
+var (
+	ErrCSRFBadOrigin  = errors.New("csrf: origin does not match")
+	ErrCSRFNoReferer  = errors.New("csrf: referer missing")
+	ErrCSRFBadReferer = errors.New("csrf: referer does not match")
+	ErrCSRFNoToken    = errors.New("csrf: token missing")
+	ErrCSRFBadToken   = errors.New("csrf: token invalid")
+)
+
+// CSRFOption configures CSRF.
+type CSRFOption func(*csrf)
+
+type csrf struct {
+	key            []byte
+	cookie         http.Cookie
+	field          string
+	header         string
+	trustedOrigins []string
+	exempt         func(*http.Request) bool
+	session        func(*http.Request) string
+	failure        http.Handler
+}
+
+type csrfContextKey int
+
+const (
+	csrfTokenKey csrfContextKey = iota
+	csrfFailureKey
+)
+
+// csrfState is the token of a request and the form field it is submitted in.
+type csrfState struct {
+	token []byte
+	field string
+}
+
+// CSRF returns middleware protecting against cross-site request forgery with signed
+// double-submit tokens. Each client gets a random token in a cookie signed with key,
+// and requests with an unsafe method must echo it in the X-CSRF-Token header or the
+// csrf_token form field. Tokens handed out to pages are masked with a fresh one-time pad
+// on each request, so that they do not leak through compression (BREACH).
+//
+// Requests with an unsafe method must also come from the same origin, as told by their
+// Origin header or, on HTTPS, their Referer header, or from one of the trusted origins.
+// GET, HEAD, OPTIONS and TRACE requests are exempt. Rejected requests are answered with
+// 403 Forbidden; the reason is available from CSRFFailureReason.
+//
+// The scheme of the same origin comes from the TLS state or the URL of the request.
+// Behind a proxy terminating TLS, TrustedProxyHeaders or ProxyHeaders must run before
+// CSRF to restore it, or else same-origin HTTPS requests are rejected.
+//
+// Tokens are not bound to a session by default: a host able to set cookies for the
+// domain, such as a sibling subdomain, can plant a cookie whose token it knows.
+// CSRFSessionKey binds them to the session of the request instead.
+//
+// key must be at least 32 bytes long and kept secret; a key shared by several servers
+// allows them to verify each other's tokens.
+//
+// Forms include the token with the csrfField function of TemplateFuncMap:
+//
+//  <form method="POST" action="/transfer">
+//  	{{csrfField}}
+//  	...
+//  </form>
+//
+// Example:
+//
+//  _, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+//  proxy := handlers.TrustedProxyHeaders(handlers.TrustedNetworks(proxies))
+//  protect := handlers.CSRF(key, handlers.CSRFTrustedOrigins("https://admin.example.com"))
+//  http.ListenAndServe(":1123", handlers.NewChain(proxy, protect).Then(r))
+func CSRF(key []byte, opts ...CSRFOption) Middleware {
+	if len(key) < 32 {
+		panic("http: CSRF requires a key of at least 32 bytes")
+	}
+	c := &csrf{
+		key: key,
+		cookie: http.Cookie{
+			Name:     CSRFCookie,
+			Path:     "/",
+			MaxAge:   int((12 * time.Hour).Seconds()),
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		},
+		field:  CSRFField,
+		header: CSRFHeader,
+		exempt:  func(*http.Request) bool { return false },
+		session: func(*http.Request) string { return "" },
+		failure: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, fmt.Sprintf("%s - %s", http.StatusText(http.StatusForbidden), CSRFFailureReason(r)), http.StatusForbidden)
+		}),
+	}
+	for _, option := range opts {
+		option(c)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Cookie")
+
+			session := c.session(r)
+			token := c.cookieToken(r, session)
+			if token == nil {
+				token = make([]byte, csrfTokenLength)
+				if _, err := rand.Read(token); err != nil {
+					panic(err)
+				}
+				cookie := c.cookie
+				cookie.Value = c.sign(token, session)
+				http.SetCookie(w, &cookie)
+			}
+			r = r.WithContext(context.WithValue(r.Context(), csrfTokenKey, &csrfState{token: token, field: c.field}))
+
+			if !safeMethod(r.Method) && !c.exempt(r) {
+				if err := c.verify(r, token); err != nil {
+					c.failure.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), csrfFailureKey, err)))
+					return
+				}
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+//
+// Functional options for configuring CSRF.
+//
+
+// CSRFCookieName sets the name of the cookie holding the token.
+func CSRFCookieName(name string) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.Name = name
+	}
+}
+
+// CSRFCookiePath sets the path of the cookie, "/" by default.
+func CSRFCookiePath(path string) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.Path = path
+	}
+}
+
+// CSRFCookieDomain sets the domain of the cookie. By default the cookie is only
+// sent to the host that set it.
+func CSRFCookieDomain(domain string) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.Domain = domain
+	}
+}
+
+// CSRFCookieMaxAge sets the lifetime of the cookie, 12 hours by default.
+// A maxAge of 0 makes it a session cookie.
+func CSRFCookieMaxAge(maxAge time.Duration) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.MaxAge = int(maxAge.Seconds())
+	}
+}
+
+// CSRFCookieSecure sets whether the cookie is only sent over HTTPS, true by default.
+// It should only be disabled for development over plain HTTP.
+func CSRFCookieSecure(secure bool) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.Secure = secure
+	}
+}
+
+// CSRFCookieSameSite sets the SameSite attribute of the cookie, Lax by default.
+func CSRFCookieSameSite(mode http.SameSite) CSRFOption {
+	return func(c *csrf) {
+		c.cookie.SameSite = mode
+	}
+}
+
+// CSRFFieldName sets the name of the form field carrying the token.
+func CSRFFieldName(name string) CSRFOption {
+	return func(c *csrf) {
+		c.field = name
+	}
+}
+
+// CSRFHeaderName sets the name of the header carrying the token.
+func CSRFHeaderName(name string) CSRFOption {
+	return func(c *csrf) {
+		c.header = name
+	}
+}
+
+// CSRFTrustedOrigins allows requests from the given origins, such as
+// "https://admin.example.com", besides those from the same origin.
+func CSRFTrustedOrigins(origins ...string) CSRFOption {
+	return func(c *csrf) {
+		for _, origin := range origins {
+			c.trustedOrigins = append(c.trustedOrigins, strings.ToLower(strings.TrimSuffix(origin, "/")))
+		}
+	}
+}
+
+// CSRFExempt exempts the requests for which fn returns true from the checks,
+// such as webhooks authenticated by other means.
+func CSRFExempt(fn func(*http.Request) bool) CSRFOption {
+	return func(c *csrf) {
+		c.exempt = fn
+	}
+}
+
+// CSRFSessionKey binds tokens to the session of the request, as identified by fn,
+// such as the session ID or the authenticated user. A token issued for one session is
+// invalid in any other, so that cookies planted by a sibling subdomain cannot forge it.
+// Clients get a new token whenever their session changes.
+func CSRFSessionKey(fn func(*http.Request) string) CSRFOption {
+	return func(c *csrf) {
+		c.session = fn
+	}
+}
+
+// CSRFErrorHandler sets the handler serving rejected requests.
+func CSRFErrorHandler(h http.Handler) CSRFOption {
+	return func(c *csrf) {
+		c.failure = h
+	}
+}
+
+// CSRFToken returns a masked token for r to submit in the X-CSRF-Token header or
+// the form field, or "" if r did not pass through CSRF.
+func CSRFToken(r *http.Request) string {
+	state, ok := r.Context().Value(csrfTokenKey).(*csrfState)
+	if !ok {
+		return ""
+	}
+	return maskToken(state.token)
+}
+
+// CSRFTemplateField returns a hidden input field carrying a masked token for r,
+// or "" if r did not pass through CSRF.
+func CSRFTemplateField(r *http.Request) template.HTML {
+	state, ok := r.Context().Value(csrfTokenKey).(*csrfState)
+	if !ok {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(state.field), maskToken(state.token)))
+}
+
+// CSRFFailureReason returns the reason a request was rejected by CSRF,
+// for use in a CSRFErrorHandler.
+func CSRFFailureReason(r *http.Request) error {
+	err, _ := r.Context().Value(csrfFailureKey).(error)
+	return err
+}
+
+// safeMethod reports whether method is safe as defined by RFC 7231.
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// verify checks the origin and token of a request with an unsafe method.
+func (c *csrf) verify(r *http.Request, token []byte) error {
+	self := "http://" + strings.ToLower(r.Host)
+	secure := r.TLS != nil || r.URL.Scheme == "https"
+	if secure {
+		self = "https://" + strings.ToLower(r.Host)
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if !c.allowedOrigin(strings.ToLower(origin), self) {
+			return ErrCSRFBadOrigin
+		}
+	} else if secure {
+		// Browsers always send a Referer with same-origin HTTPS requests, unless suppressed
+		// by a referrer policy, which then also has to allow same-origin requests.
+		referer := r.Header.Get("Referer")
+		if referer == "" {
+			return ErrCSRFNoReferer
+		}
+		u, err := url.Parse(referer)
+		if err != nil || !c.allowedOrigin(strings.ToLower(u.Scheme+"://"+u.Host), self) {
+			return ErrCSRFBadReferer
+		}
+	}
+
+	sent := r.Header.Get(c.header)
+	if sent == "" {
+		sent = r.PostFormValue(c.field)
+	}
+	if sent == "" {
+		return ErrCSRFNoToken
+	}
+	if subtle.ConstantTimeCompare(unmaskToken(sent), token) != 1 {
+		return ErrCSRFBadToken
+	}
+	return nil
+}
+
+func (c *csrf) allowedOrigin(origin, self string) bool {
+	if origin == self {
+		return true
+	}
+	for _, trusted := range c.trustedOrigins {
+		if origin == trusted {
+			return true
+		}
+	}
+	return false
+}
+
+// cookieToken returns the token of the signed cookie of r, or nil if it is missing or
+// invalid for session.
+func (c *csrf) cookieToken(r *http.Request, session string) []byte {
+	cookie, err := r.Cookie(c.cookie.Name)
+	if err != nil {
+		return nil
+	}
+	i := strings.IndexByte(cookie.Value, '.')
+	if i < 0 {
+		return nil
+	}
+	token, err := base64.RawURLEncoding.DecodeString(cookie.Value[:i])
+	if err != nil || len(token) != csrfTokenLength {
+		return nil
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(cookie.Value[i+1:])
+	if err != nil || !hmac.Equal(mac, c.mac(token, session)) {
+		return nil
+	}
+	return token
+}
+
+// sign returns the cookie value of token for session.
+func (c *csrf) sign(token []byte, session string) string {
+	return base64.RawURLEncoding.EncodeToString(token) + "." +
+		base64.RawURLEncoding.EncodeToString(c.mac(token, session))
+}
+
+// mac authenticates token for session. Tokens are fixed-length, so appending
+// the session is unambiguous.
+func (c *csrf) mac(token []byte, session string) []byte {
+	if session == "" {
+		return crypto.HmacSha256(c.key, token)
+	}
+	return crypto.HmacSha256(c.key, append(append([]byte{}, token...), session...))
+}
+
+// maskToken returns a one-time pad followed by token XORed with it.
+func maskToken(token []byte) string {
+	masked := make([]byte, 2*len(token))
+	if _, err := rand.Read(masked[:len(token)]); err != nil {
+		panic(err)
+	}
+	for i, b := range token {
+		masked[len(token)+i] = masked[i] ^ b
+	}
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+func unmaskToken(s string) []byte {
+	masked, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(masked) != 2*csrfTokenLength {
+		return nil
+	}
+	token := make([]byte, csrfTokenLength)
+	for i := range token {
+		token[i] = masked[i] ^ masked[csrfTokenLength+i]
+	}
+	return token
+}
@@ -0,0 +1,220 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestCSRF(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	var reason error
+	protect := handlers.CSRF(key,
+		handlers.CSRFTrustedOrigins("https://admin.example.com/"),
+		handlers.CSRFExempt(func(r *http.Request) bool { return r.URL.Path == "/hooks" }),
+		handlers.CSRFErrorHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reason = handlers.CSRFFailureReason(r)
+			w.WriteHeader(http.StatusForbidden)
+		})),
+	)
+	var tokens []string
+	h := protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, handlers.CSRFToken(r))
+	}))
+
+	// A first GET hands out the signed cookie and a masked token.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	cookies := rec.Result().Cookies()
+	if rec.Code != http.StatusOK || len(cookies) != 1 || cookies[0].Name != handlers.CSRFCookie {
+		t.Fatalf("GET: code %d, cookies %v", rec.Code, cookies)
+	}
+	cookie := cookies[0]
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode {
+		t.Errorf("cookie attributes = %+v", cookie)
+	}
+	// Tokens are masked afresh on each request but stay valid.
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if len(rec.Result().Cookies()) != 0 {
+		t.Error("GET with a valid cookie set a new one")
+	}
+	if len(tokens) != 2 || tokens[0] == tokens[1] {
+		t.Fatalf("tokens = %q, want two different masked tokens", tokens)
+	}
+
+	tampered := *cookie
+	tampered.Value = strings.Replace(cookie.Value, ".", ".A", 1)
+	tests := []struct {
+		name    string
+		path    string
+		https   bool
+		cookie  *http.Cookie
+		headers map[string]string
+		form    url.Values
+		wantErr error
+	}{
+		{name: "header token", cookie: cookie, headers: map[string]string{handlers.CSRFHeader: tokens[0]}},
+		{name: "token of another request", cookie: cookie, headers: map[string]string{handlers.CSRFHeader: tokens[1]}},
+		{name: "form token", cookie: cookie, form: url.Values{handlers.CSRFField: {tokens[0]}}},
+		{name: "same origin", cookie: cookie, headers: map[string]string{"Origin": "http://example.com", handlers.CSRFHeader: tokens[0]}},
+		{name: "trusted origin", cookie: cookie, headers: map[string]string{"Origin": "https://admin.example.com", handlers.CSRFHeader: tokens[0]}},
+		{name: "exempt", path: "/hooks"},
+		{name: "missing token", cookie: cookie, wantErr: handlers.ErrCSRFNoToken},
+		{name: "malformed token", cookie: cookie, headers: map[string]string{handlers.CSRFHeader: "abc"}, wantErr: handlers.ErrCSRFBadToken},
+		{name: "missing cookie", headers: map[string]string{handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadToken},
+		{name: "tampered cookie", cookie: &tampered, headers: map[string]string{handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadToken},
+		{name: "cross origin", cookie: cookie, headers: map[string]string{"Origin": "https://evil.example", handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadOrigin},
+		{name: "scheme mismatch", https: true, cookie: cookie, headers: map[string]string{"Origin": "http://example.com", handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadOrigin},
+		{name: "https referer", https: true, cookie: cookie, headers: map[string]string{"Referer": "https://example.com/form", handlers.CSRFHeader: tokens[0]}},
+		{name: "https missing referer", https: true, cookie: cookie, headers: map[string]string{handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFNoReferer},
+		{name: "https cross-origin referer", https: true, cookie: cookie, headers: map[string]string{"Referer": "https://evil.example/", handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadReferer},
+		{name: "https plain-http referer", https: true, cookie: cookie, headers: map[string]string{"Referer": "http://example.com/form", handlers.CSRFHeader: tokens[0]}, wantErr: handlers.ErrCSRFBadReferer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason = nil
+			path := tt.path
+			if path == "" {
+				path = "/transfer"
+			}
+			req := httptest.NewRequest("POST", path, strings.NewReader(tt.form.Encode()))
+			if tt.form != nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+			if tt.https {
+				req.TLS = &tls.ConnectionState{}
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			wantCode := http.StatusOK
+			if tt.wantErr != nil {
+				wantCode = http.StatusForbidden
+			}
+			if rec.Code != wantCode || reason != tt.wantErr {
+				t.Errorf("code = %d, reason = %v; want %d, %v", rec.Code, reason, wantCode, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCSRFTemplateField(t *testing.T) {
+	h := handlers.CSRF([]byte("0123456789abcdef0123456789abcdef"), handlers.CSRFFieldName("token"))
+	var field string
+	h(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		field = string(handlers.CSRFTemplateField(r))
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !strings.HasPrefix(field, `<input type="hidden" name="token" value="`) {
+		t.Errorf("field = %q", field)
+	}
+	if got := handlers.CSRFTemplateField(httptest.NewRequest("GET", "/", nil)); got != "" {
+		t.Errorf("field without CSRF = %q, want empty", got)
+	}
+}
+
+func TestCSRFShortKey(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("CSRF with a short key did not panic")
+		}
+	}()
+	handlers.CSRF([]byte("short"))
+}
+
+func TestCSRFSessionKey(t *testing.T) {
+	protect := handlers.CSRF([]byte("0123456789abcdef0123456789abcdef"),
+		handlers.CSRFSessionKey(func(r *http.Request) string { return r.Header.Get("X-Session") }))
+	var token string
+	h := protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = handlers.CSRFToken(r)
+	}))
+
+	// A cookie issued for the session of an attacker, as planted by a sibling subdomain.
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Session", "attacker")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	cookie := rec.Result().Cookies()[0]
+
+	tests := []struct {
+		session  string
+		wantCode int
+	}{
+		{"attacker", http.StatusOK},
+		{"victim", http.StatusForbidden},
+		{"", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.session, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/transfer", nil)
+			req.Header.Set("X-Session", tt.session)
+			req.Header.Set(handlers.CSRFHeader, token)
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if reissued := len(rec.Result().Cookies()) != 0; reissued != (tt.wantCode != http.StatusOK) {
+				t.Errorf("reissued cookie = %v", reissued)
+			}
+		})
+	}
+}
+
+func TestCSRFBehindProxy(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("192.0.2.0/24")
+	var token string
+	protect := handlers.CSRF([]byte("0123456789abcdef0123456789abcdef"))
+	h := protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = handlers.CSRFToken(r)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	cookie := rec.Result().Cookies()[0]
+
+	proxied := handlers.NewChain(handlers.TrustedProxyHeaders(handlers.TrustedNetworks(proxies)), protect).
+		Then(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	tests := []struct {
+		name     string
+		h        http.Handler
+		wantCode int
+	}{
+		{"proxy headers first", proxied, http.StatusOK},
+		{"no proxy headers", h, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The proxy terminated TLS, so the request itself is plain HTTP.
+			req := httptest.NewRequest("POST", "/transfer", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("Origin", "https://example.com")
+			req.Header.Set(handlers.CSRFHeader, token)
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			tt.h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxDecompressedSize is the default limit for decompressed request bodies.
+const DefaultMaxDecompressedSize = 10 << 20
+
+// zstdMinWindow is the smallest window size limit used for zstd request bodies.
+const zstdMinWindow = 8 << 20
+
+// DecompressOption configures the Decompress middleware.
+type DecompressOption func(*decompressor)
+
+type decompressor struct {
+	maxSize   int64
+	encodings []string
+}
+
+// Decompress returns middleware that transparently decodes request bodies sent with
+// a Content-Encoding of gzip, deflate, br or zstd, including stacked encodings
+// such as "gzip, br". Deflate bodies are zlib streams as HTTP specifies, though the raw
+// DEFLATE streams sent by some clients are accepted too. The Content-Encoding and
+// Content-Length headers are removed from decoded requests.
+//
+// Requests with an unsupported encoding are answered with 415 Unsupported Media Type
+// and an Accept-Encoding header listing the supported encodings, and malformed bodies
+// with 400 Bad Request. Once a handler reads more than the maximum decompressed size,
+// the read fails with an *http.MaxBytesError and, unless the handler has already
+// started the response, 413 Request Entity Too Large is sent in place of its response.
+//
+// Example:
+//
+//  ingest := handlers.Decompress(handlers.DecompressionMaxSize(64 << 20))
+//  http.Handle("/ingest", ingest(ingestHandler))
+func Decompress(opts ...DecompressOption) Middleware {
+	d := &decompressor{
+		maxSize:   DefaultMaxDecompressedSize,
+		encodings: []string{EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd},
+	}
+	for _, option := range opts {
+		option(d)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			codings := contentCodings(r.Header.Get("Content-Encoding"))
+			if len(codings) == 0 || r.Body == nil || r.Body == http.NoBody {
+				h.ServeHTTP(w, r)
+				return
+			}
+			for _, coding := range codings {
+				if !d.supports(coding) {
+					w.Header().Set("Accept-Encoding", strings.Join(d.encodings, ", "))
+					http.Error(w, fmt.Sprintf("Unsupported content encoding %q", coding), http.StatusUnsupportedMediaType)
+					return
+				}
+			}
+
+			body, err := decodeBody(r.Body, codings, d.maxSize)
+			if err != nil {
+				http.Error(w, "Malformed request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			lw := &limitResponseWriter{ResponseWriter: w}
+			body.exceeded = &lw.exceeded
+			defer body.Close()
+
+			r.Body = body
+			r.ContentLength = -1
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			h.ServeHTTP(lw, r)
+			if lw.exceeded && !lw.wroteHeader {
+				lw.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
+
+//
+// Functional options for configuring Decompress.
+//
+
+// DecompressionMaxSize sets the maximum size of a decompressed request body in bytes.
+// A size of 0 or less removes the limit.
+func DecompressionMaxSize(size int64) DecompressOption {
+	return func(d *decompressor) {
+		d.maxSize = size
+	}
+}
+
+// DecompressionEncodings restricts the accepted encodings to the given ones.
+// Unsupported encodings are ignored.
+func DecompressionEncodings(encodings ...string) DecompressOption {
+	return func(d *decompressor) {
+		d.encodings = nil
+		for _, enc := range encodings {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			for _, supported := range defaultEncodings {
+				if enc == supported {
+					d.encodings = append(d.encodings, enc)
+				}
+			}
+		}
+	}
+}
+
+func (d *decompressor) supports(coding string) bool {
+	if coding == "x-gzip" {
+		coding = EncodingGzip
+	}
+	for _, enc := range d.encodings {
+		if coding == enc {
+			return true
+		}
+	}
+	return false
+}
+
+// contentCodings returns the lower-cased codings of a Content-Encoding header in the order
+// they were applied, leaving out identity.
+func contentCodings(header string) []string {
+	var codings []string
+	for _, coding := range strings.Split(header, ",") {
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "" && coding != "identity" {
+			codings = append(codings, coding)
+		}
+	}
+	return codings
+}
+
+// decodedBody is a request body decoded by a stack of decoders, limited to max bytes.
+type decodedBody struct {
+	r        io.Reader
+	closers  []io.Closer
+	max      int64
+	n        int64
+	exceeded *bool
+}
+
+// decodeBody undoes the codings of body, the last applied coding first.
+func decodeBody(body io.ReadCloser, codings []string, max int64) (*decodedBody, error) {
+	b := &decodedBody{r: body, closers: []io.Closer{body}, max: max}
+	for i := len(codings) - 1; i >= 0; i-- {
+		switch codings[i] {
+		case EncodingGzip, "x-gzip":
+			gr, err := gzip.NewReader(b.r)
+			if err != nil {
+				b.Close()
+				return nil, err
+			}
+			b.r = gr
+			b.closers = append(b.closers, gr)
+		case EncodingDeflate:
+			dr, err := newDeflateReader(b.r)
+			if err != nil {
+				b.Close()
+				return nil, err
+			}
+			b.r = dr
+			b.closers = append(b.closers, dr)
+		case EncodingBrotli:
+			b.r = brotli.NewReader(b.r)
+		case EncodingZstd:
+			// Bound the window the decoder allocates, allowing the 8 MiB window of common encoders.
+			window := uint64(zstdMinWindow)
+			if max > zstdMinWindow {
+				window = uint64(max)
+			}
+			if window > zstd.MaxWindowSize {
+				window = zstd.MaxWindowSize
+			}
+			zr, err := zstd.NewReader(b.r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(window))
+			if err != nil {
+				b.Close()
+				return nil, err
+			}
+			b.r = zr
+			b.closers = append(b.closers, zr.IOReadCloser())
+		}
+	}
+	return b, nil
+}
+
+// newDeflateReader decodes the zlib stream of the deflate coding (RFC 9110 section 8.4.1.2).
+// Bodies not starting with a zlib header are read as raw DEFLATE, as some clients send them.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, _ := br.Peek(2)
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+func (b *decodedBody) Read(p []byte) (int, error) {
+	if b.max <= 0 {
+		return b.r.Read(p)
+	}
+	if b.n >= b.max {
+		// Check for more data before reporting the limit, so that bodies of exactly max bytes are accepted.
+		var one [1]byte
+		if n, err := b.r.Read(one[:]); n == 0 {
+			return 0, err
+		}
+		if b.exceeded != nil {
+			*b.exceeded = true
+		}
+		return 0, &http.MaxBytesError{Limit: b.max}
+	}
+	if int64(len(p)) > b.max-b.n {
+		p = p[:b.max-b.n]
+	}
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// Close closes the decoders and the original body, innermost last.
+func (b *decodedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if cerr := b.closers[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	b.closers = nil
+	return err
+}
+
+// limitResponseWriter replaces the response with 413 Request Entity Too Large
+// if the request body exceeded its limit before the response was started.
+type limitResponseWriter struct {
+	http.ResponseWriter
+
+	exceeded    bool
+	wroteHeader bool
+	replaced    bool
+}
+
+func (w *limitResponseWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if w.exceeded {
+		w.replaced = true
+		http.Error(w.ResponseWriter, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *limitResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *limitResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok && !w.replaced {
+		f.Flush()
+	}
+}
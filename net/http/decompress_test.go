@@ -0,0 +1,130 @@
+package http_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+// encode applies the coding to b.
+func encode(t *testing.T, coding string, b []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch coding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		var err error
+		if w, err = zstd.NewWriter(&buf); err != nil {
+			t.Fatal(err)
+		}
+	default:
+		t.Fatalf("unknown coding %q", coding)
+	}
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	payload := []byte(strings.Repeat("hello, world\n", 100))
+	corruptZlib := encode(t, "deflate", payload)
+	corruptZlib[len(corruptZlib)-1] ^= 0xff
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		opts     []handlers.DecompressOption
+		wantCode int
+		wantBody string
+	}{
+		{name: "identity", body: payload, wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "gzip", encoding: "gzip", body: encode(t, "gzip", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "x-gzip", encoding: "x-gzip", body: encode(t, "gzip", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "deflate", encoding: "deflate", body: encode(t, "deflate", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "raw deflate", encoding: "deflate", body: encode(t, "raw-deflate", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "br", encoding: "br", body: encode(t, "br", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "zstd", encoding: "zstd", body: encode(t, "zstd", payload), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "stacked", encoding: "gzip, br", body: encode(t, "br", encode(t, "gzip", payload)), wantCode: http.StatusOK, wantBody: string(payload)},
+		{name: "stacked with identity", encoding: "identity, zstd, GZIP", body: encode(t, "gzip", encode(t, "zstd", payload)), wantCode: http.StatusOK, wantBody: string(payload)},
+		{
+			name:     "exactly the limit",
+			encoding: "gzip",
+			body:     encode(t, "gzip", payload),
+			opts:     []handlers.DecompressOption{handlers.DecompressionMaxSize(int64(len(payload)))},
+			wantCode: http.StatusOK,
+			wantBody: string(payload),
+		},
+		{
+			name:     "over the limit",
+			encoding: "gzip",
+			body:     encode(t, "gzip", payload),
+			opts:     []handlers.DecompressOption{handlers.DecompressionMaxSize(int64(len(payload)) - 1)},
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+		{name: "unknown coding", encoding: "compress", body: payload, wantCode: http.StatusUnsupportedMediaType},
+		{
+			name:     "coding not enabled",
+			encoding: "br",
+			body:     encode(t, "br", payload),
+			opts:     []handlers.DecompressOption{handlers.DecompressionEncodings("gzip")},
+			wantCode: http.StatusUnsupportedMediaType,
+		},
+		{name: "corrupt gzip header", encoding: "gzip", body: payload, wantCode: http.StatusBadRequest},
+		{name: "corrupt zlib checksum", encoding: "deflate", body: corruptZlib, wantCode: http.StatusBadRequest},
+		{name: "corrupt zstd", encoding: "zstd", body: payload, wantCode: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handlers.Decompress(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "" {
+					t.Errorf("Content-Encoding = %q, want it removed", r.Header.Get("Content-Encoding"))
+				}
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				w.Write(b)
+			}))
+			req := httptest.NewRequest("POST", "/", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d: %s", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if tt.wantCode == http.StatusUnsupportedMediaType && rec.Header().Get("Accept-Encoding") == "" {
+				t.Error("415 without Accept-Encoding")
+			}
+			if tt.wantCode == http.StatusOK && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
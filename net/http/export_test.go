@@ -0,0 +1,9 @@
+package http
+
+import "time"
+
+// SetLogClock makes the logging handlers read the time from now until restore is called.
+func SetLogClock(now func() time.Time) (restore func()) {
+	logClock = now
+	return func() { logClock = time.Now }
+}
@@ -39,7 +39,8 @@ var (
 // configured not to) strip these headers from client requests, or where these
 // headers are accepted "as is" from a remote client (e.g. when Go is not behind
 // a proxy), can manifest as a vulnerability if your application uses these
-// headers for validating the 'trustworthiness' of a request.
+// headers for validating the 'trustworthiness' of a request. Use
+// TrustedProxyHeaders to honour them only for requests from known proxies.
 func ProxyHeaders(h http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		// Set the remote IP with the value passed from the proxy.
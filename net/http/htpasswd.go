@@ -0,0 +1,185 @@
+package http
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Htpasswd holds the password hashes of an Apache htpasswd file by user name.
+// Hashes may be bcrypt ("$2y$"), Apache MD5 ("$apr1$") or SHA-1 ("{SHA}").
+type Htpasswd map[string]string
+
+// LoadHtpasswdFile reads an htpasswd file.
+func LoadHtpasswdFile(path string) (Htpasswd, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return LoadHtpasswd(f)
+}
+
+// LoadHtpasswd reads htpasswd entries of the form "user:hash", one per line.
+// Blank lines and lines starting with "#" are skipped.
+func LoadHtpasswd(r io.Reader) (Htpasswd, error) {
+	users := make(Htpasswd)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.IndexByte(text, ':')
+		if i <= 0 {
+			return nil, fmt.Errorf("htpasswd: line %d: missing user name", line)
+		}
+		user, hash := text[:i], text[i+1:]
+		if !supportedHash(hash) {
+			return nil, fmt.Errorf("htpasswd: line %d: unsupported hash for user %q", line, user)
+		}
+		users[user] = hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Validate reports whether password matches the hash of user, for use with BasicAuth.
+// Passwords of unknown users are checked against the costliest hash in the file,
+// so that they take as long as wrong passwords of known users.
+func (h Htpasswd) Validate(user, password string) bool {
+	hash, ok := h[user]
+	if !ok {
+		checkHash(h.costliestHash(), password)
+		return false
+	}
+	return checkHash(hash, password)
+}
+
+// costliestHash returns the bcrypt hash with the highest cost, or else an Apache MD5 or SHA-1 hash.
+func (h Htpasswd) costliestHash() string {
+	var costliest string
+	maxCost := -1
+	for _, hash := range h {
+		if cost := hashCost(hash); cost > maxCost {
+			costliest, maxCost = hash, cost
+		}
+	}
+	return costliest
+}
+
+// hashCost ranks hashes by the work of checking them: bcrypt hashes by their cost,
+// above Apache MD5 hashes, above SHA-1 hashes.
+func hashCost(hash string) int {
+	switch {
+	case strings.HasPrefix(hash, "$2"):
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return 0
+		}
+		return 2 + cost
+	case strings.HasPrefix(hash, "$apr1$"):
+		return 1
+	}
+	return 0
+}
+
+// checkHash reports whether password matches hash.
+func checkHash(hash, password string) bool {
+	switch {
+	case strings.HasPrefix(hash, "$2"):
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+	case strings.HasPrefix(hash, "$apr1$"):
+		parts := strings.SplitN(hash, "$", 4)
+		if len(parts) != 4 {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(hash), []byte(apr1(password, parts[2]))) == 1
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		return subtle.ConstantTimeCompare([]byte(hash[5:]), []byte(base64.StdEncoding.EncodeToString(sum[:]))) == 1
+	}
+	return false
+}
+
+func supportedHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$") ||
+		strings.HasPrefix(hash, "$apr1$") || strings.HasPrefix(hash, "{SHA}")
+}
+
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apr1 returns the Apache MD5 crypt hash of password with salt.
+func apr1(password, salt string) string {
+	const magic = "$apr1$"
+	if len(salt) > 8 {
+		salt = salt[:8]
+	}
+	pw := []byte(password)
+
+	alt := md5.Sum([]byte(password + salt + password))
+	ctx := md5.New()
+	ctx.Write([]byte(password + magic + salt))
+	for i := len(pw); i > 0; i -= 16 {
+		n := i
+		if n > 16 {
+			n = 16
+		}
+		ctx.Write(alt[:n])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(pw[:1])
+		}
+	}
+	final := ctx.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 != 0 {
+			round.Write(pw)
+		} else {
+			round.Write(final)
+		}
+		if i%3 != 0 {
+			round.Write([]byte(salt))
+		}
+		if i%7 != 0 {
+			round.Write(pw)
+		}
+		if i&1 != 0 {
+			round.Write(final)
+		} else {
+			round.Write(pw)
+		}
+		final = round.Sum(nil)
+	}
+
+	var b strings.Builder
+	b.WriteString(magic + salt + "$")
+	to64 := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			b.WriteByte(apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	for _, g := range [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}} {
+		to64(uint32(final[g[0]])<<16|uint32(final[g[1]])<<8|uint32(final[g[2]]), 4)
+	}
+	to64(uint32(final[11]), 2)
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package http_test
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestHtpasswdValidate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("hunter2"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := strings.Join([]string{
+		"# users",
+		"",
+		"bcrypt:" + string(hash),
+		"apr1:$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/",
+		"sha:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+	}, "\n")
+	users, err := handlers.LoadHtpasswd(strings.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		user     string
+		password string
+		want     bool
+	}{
+		{"bcrypt", "hunter2", true},
+		{"bcrypt", "hunter3", false},
+		{"apr1", "secret", true},
+		{"apr1", "Secret", false},
+		{"sha", "password", true},
+		{"sha", "passwort", false},
+		{"unknown", "hunter2", false},
+		{"unknown", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.user+"/"+tt.password, func(t *testing.T) {
+			if got := users.Validate(tt.user, tt.password); got != tt.want {
+				t.Errorf("Validate(%q, %q) = %v, want %v", tt.user, tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadHtpasswdErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"missing user", ":$apr1$abcdefgh$h9FWgUz3n9YxylKLlR5SQ/"},
+		{"missing colon", "alice"},
+		{"crypt hash", "alice:abJnggxhB/yWI"},
+		{"plain text", "alice:secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := handlers.LoadHtpasswd(strings.NewReader(tt.file)); err == nil {
+				t.Error("LoadHtpasswd() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+// JWTValidator returns a TokenValidator accepting JSON Web Tokens verified with the keys
+// returned by keys and validated by v. The principal is named after the "sub" claim
+// and carries all claims of the token.
+func JWTValidator(keys crypto.JWTKeyFunc, v crypto.JWTValidation) TokenValidator {
+	return func(_ context.Context, token string) (*Principal, error) {
+		claims, err := crypto.ParseJWT(token, keys, v)
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{Name: claims.Subject(), Scheme: AuthSchemeJWT, Claims: claims}, nil
+	}
+}
+
+// JWTAuth returns middleware that requires a bearer JSON Web Token verified with the keys
+// returned by keys and validated by v, answering other requests with 401 Unauthorized.
+// The claims of the token are available from ClaimsFromContext.
+//
+// Example:
+//
+//  set, err := crypto.ParseJWKS(jwks)
+//  if err != nil {
+//  	log.Fatal(err)
+//  }
+//  auth := handlers.JWTAuth(set.KeyFunc(), crypto.JWTValidation{
+//  	Algorithms: []string{crypto.RS256},
+//  	Issuer:     "https://issuer.example.com",
+//  	Audience:   "api",
+//  	Leeway:     30 * time.Second,
+//  })
+//  http.ListenAndServe(":1123", auth(r))
+func JWTAuth(keys crypto.JWTKeyFunc, v crypto.JWTValidation) Middleware {
+	return BearerAuth(JWTValidator(keys, v))
+}
+
+// ClaimsFromContext returns the claims of the token authenticated by JWTAuth.
+func ClaimsFromContext(ctx context.Context) (crypto.Claims, bool) {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok || p.Claims == nil {
+		return nil, false
+	}
+	return crypto.Claims(p.Claims), true
+}
+
+// JWKSHandler returns a handler serving the public keys of set as a JSON Web Key Set,
+// for clients verifying the tokens signed with its private keys.
+func JWKSHandler(set *crypto.JWKS) http.Handler {
+	body, err := json.Marshal(set.Public())
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(body)
+	})
+}
@@ -0,0 +1,104 @@
+package http_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofunct/functional/crypto"
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestJWTAuth(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := crypto.NewJWK(key, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	set := &crypto.JWKS{Keys: []*crypto.JWK{jwk}}
+	exp := time.Now().Add(time.Hour).Unix()
+	sign := func(claims crypto.Claims, alg string, key interface{}) string {
+		token, err := crypto.SignJWT(claims, alg, key, "k1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	tests := []struct {
+		name     string
+		token    string
+		wantCode int
+		wantSub  string
+	}{
+		{"valid", sign(crypto.Claims{"sub": "alice", "aud": []string{"web", "api"}, "exp": exp}, crypto.EdDSA, key), http.StatusOK, "alice"},
+		{"wrong audience", sign(crypto.Claims{"sub": "alice", "aud": "web", "exp": exp}, crypto.EdDSA, key), http.StatusUnauthorized, ""},
+		{"missing exp", sign(crypto.Claims{"sub": "alice", "aud": "api"}, crypto.EdDSA, key), http.StatusUnauthorized, ""},
+		{"algorithm not allowed", sign(crypto.Claims{"sub": "alice", "aud": "api", "exp": exp}, crypto.HS256, []byte("k1")), http.StatusUnauthorized, ""},
+		{"malformed", "not.a.token", http.StatusUnauthorized, ""},
+		{"no token", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := handlers.JWTAuth(set.Public().KeyFunc(), crypto.JWTValidation{
+				Algorithms: []string{crypto.EdDSA},
+				Audience:   "api",
+				RequireExp: true,
+			})
+			var sub string
+			h := auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				claims, ok := handlers.ClaimsFromContext(r.Context())
+				if !ok {
+					t.Error("no claims in context")
+				}
+				sub = claims.Subject()
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if sub != tt.wantSub {
+				t.Errorf("sub = %q, want %q", sub, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := crypto.NewJWK(key, "k1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret, err := crypto.NewJWK([]byte("secret"), "hmac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	handlers.JWKSHandler(&crypto.JWKS{Keys: []*crypto.JWK{jwk, secret}}).ServeHTTP(rec, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/jwk-set+json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	set, err := crypto.ParseJWKS(rec.Body.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "k1" || set.Keys[0].D != "" {
+		t.Errorf("published keys = %s, want only the public key k1", rec.Body.String())
+	}
+}
@@ -13,12 +13,20 @@ import (
 // Logging
 
 // FormatterParams is the structure any formatter will be handed when time to log comes
+//
+// Duration is the time taken to serve the request, and BytesIn the number of
+// request body bytes read by the handler. RequestID and TraceID are set when
+// the request passed through the RequestID middleware.
 type LogFormatterParams struct {
 	Request    *http.Request
 	URL        url.URL
 	TimeStamp  time.Time
 	StatusCode int
 	Size       int
+	Duration   time.Duration
+	BytesIn    int64
+	RequestID  string
+	TraceID    string
 }
 
 // LogFormatter gives the signature of the formatter function passed to CustomLoggingHandler
@@ -34,21 +42,50 @@ type loggingHandler struct {
 }
 
 func (h loggingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	t := time.Now()
+	h.formatter(h.writer, serveLogged(h.handler, w, req))
+}
+
+// logClock is the clock of the logging handlers, replaced by tests.
+var logClock = time.Now
+
+// serveLogged serves req with h, recording the response status and size,
+// the request body size, the duration and the request and trace IDs.
+func serveLogged(h http.Handler, w http.ResponseWriter, req *http.Request) LogFormatterParams {
+	t := logClock()
+	req, _ = withCorrelation(req)
 	logger := makeLogger(w)
 	url := *req.URL
+	body := &countingReader{ReadCloser: req.Body}
+	if req.Body != nil {
+		req.Body = body
+	}
 
-	h.handler.ServeHTTP(logger, req)
+	h.ServeHTTP(logger, req)
 
-	params := LogFormatterParams{
+	id, traceID := requestCorrelation(req, w.Header())
+	return LogFormatterParams{
 		Request:    req,
 		URL:        url,
 		TimeStamp:  t,
 		StatusCode: logger.Status(),
 		Size:       logger.Size(),
+		Duration:   logClock().Sub(t),
+		BytesIn:    body.n,
+		RequestID:  id,
+		TraceID:    traceID,
 	}
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
 
-	h.formatter(h.writer, params)
+func (r *countingReader) Read(b []byte) (int, error) {
+	n, err := r.ReadCloser.Read(b)
+	r.n += int64(n)
+	return n, err
 }
 
 func makeLogger(w http.ResponseWriter) loggingResponseWriter {
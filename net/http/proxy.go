@@ -0,0 +1,291 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gofunct/functional/flag/cidrflag"
+)
+
+// IPMatcher reports whether an IP address belongs to a set, such as the trusted proxies.
+// It is implemented by *cidrflag.PrefixSet and the cidrflag flag values.
+type IPMatcher interface {
+	Contains(ip net.IP) bool
+}
+
+// ProxyHeaderFamily selects the proxy headers honoured by TrustedProxyHeaders.
+type ProxyHeaderFamily int
+
+const (
+	// XForwardedHeaders are X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Real-IP.
+	XForwardedHeaders ProxyHeaderFamily = iota
+	// ForwardedHeader is the RFC 7239 Forwarded header.
+	ForwardedHeader
+)
+
+// ProxyOption configures TrustedProxyHeaders.
+type ProxyOption func(*trustedProxies)
+
+type trustedProxies struct {
+	trusted IPMatcher
+	family  ProxyHeaderFamily
+	host    bool
+}
+
+// forwardedElement is one element of a Forwarded header, or one hop of X-Forwarded-For.
+type forwardedElement struct {
+	forNode string
+	proto   string
+	host    string
+}
+
+// TrustedProxyHeaders returns middleware that works like ProxyHeaders, but only
+// honours the proxy headers of requests whose RemoteAddr is a trusted proxy.
+//
+// The client address is found by walking the chain of forwarded addresses from
+// right to left, skipping trusted proxies, so that addresses a client prepends
+// to the chain are ignored.
+//
+// Only one family of headers is honoured, the one the trusted proxies set:
+// X-Forwarded-For, X-Forwarded-Proto, X-Forwarded-Host and X-Real-IP by default,
+// or the RFC 7239 Forwarded header, including its proto= and host= parameters and
+// quoted IPv6 addresses, with TrustedHeaders(ForwardedHeader). Headers of the other
+// family are ignored, as a proxy that does not set them passes a client's on unchanged.
+//
+// Example:
+//
+//  trusted := &cidrflag.CIDRs{}
+//  fs.Var(trusted, "trusted-proxy", "CIDR of a trusted reverse proxy")
+//  ...
+//  http.ListenAndServe(":1123", handlers.TrustedProxyHeaders(handlers.TrustedProxies(trusted))(r))
+func TrustedProxyHeaders(opts ...ProxyOption) Middleware {
+	p := &trustedProxies{
+		trusted: &cidrflag.PrefixSet{},
+		host:    true,
+	}
+	for _, option := range opts {
+		option(p)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := parseNode(r.RemoteAddr); ip != nil && p.trusted.Contains(ip) {
+				p.apply(r)
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+//
+// Functional options for configuring TrustedProxyHeaders.
+//
+
+// TrustedProxies sets the addresses of the trusted proxies.
+func TrustedProxies(trusted IPMatcher) ProxyOption {
+	return func(p *trustedProxies) {
+		p.trusted = trusted
+	}
+}
+
+// TrustedNetworks sets the networks of the trusted proxies.
+func TrustedNetworks(networks ...*net.IPNet) ProxyOption {
+	return func(p *trustedProxies) {
+		set := &cidrflag.PrefixSet{}
+		for _, n := range networks {
+			set.Add(n)
+		}
+		p.trusted = set
+	}
+}
+
+// TrustedHeaders sets the family of proxy headers set by the trusted proxies.
+func TrustedHeaders(family ProxyHeaderFamily) ProxyOption {
+	return func(p *trustedProxies) {
+		p.family = family
+	}
+}
+
+// IgnoreForwardedHost leaves the Host of requests unchanged, ignoring
+// X-Forwarded-Host and the host= parameter of Forwarded.
+func IgnoreForwardedHost() ProxyOption {
+	return func(p *trustedProxies) {
+		p.host = false
+	}
+}
+
+// apply sets the remote address, scheme and host of r from its proxy headers.
+func (p *trustedProxies) apply(r *http.Request) {
+	chain := forwardedChain(r.Header, p.family)
+	if len(chain) == 0 {
+		return
+	}
+
+	// Walk the chain right to left; the first hop that is not a trusted proxy is the client.
+	client := 0
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip := parseNode(chain[i].forNode)
+		if ip == nil {
+			// An obfuscated or unknown node; nothing before it can be attributed.
+			client = i
+			break
+		}
+		if !p.trusted.Contains(ip) {
+			client = i
+			break
+		}
+	}
+	e := chain[client]
+
+	if ip := parseNode(e.forNode); ip != nil {
+		r.RemoteAddr = ip.String()
+	}
+	if scheme := strings.ToLower(e.proto); scheme == "http" || scheme == "https" {
+		r.URL.Scheme = scheme
+	}
+	if p.host && e.host != "" {
+		r.Host = e.host
+	}
+}
+
+// forwardedChain returns the hops recorded by the proxy headers of family in h, client first.
+func forwardedChain(h http.Header, family ProxyHeaderFamily) []forwardedElement {
+	if family == ForwardedHeader {
+		return parseForwarded(strings.Join(h.Values(forwarded), ","))
+	}
+
+	var chain []forwardedElement
+	for _, v := range h.Values(xForwardedFor) {
+		for _, node := range strings.Split(v, ",") {
+			if node = strings.TrimSpace(node); node != "" {
+				chain = append(chain, forwardedElement{forNode: node})
+			}
+		}
+	}
+	if len(chain) == 0 {
+		if ip := h.Get(xRealIP); ip != "" {
+			chain = []forwardedElement{{forNode: strings.TrimSpace(ip)}}
+		}
+	}
+	if len(chain) == 0 {
+		return nil
+	}
+
+	// X-Forwarded-Proto and X-Forwarded-Host hold either one value per hop,
+	// or a single value set by the nearest proxy.
+	protos := headerList(h, xForwardedProto)
+	if len(protos) == 0 {
+		protos = headerList(h, xForwardedScheme)
+	}
+	hosts := headerList(h, xForwardedHost)
+	for i := range chain {
+		chain[i].proto = hopValue(protos, i, len(chain))
+		chain[i].host = hopValue(hosts, i, len(chain))
+	}
+	return chain
+}
+
+func headerList(h http.Header, key string) []string {
+	var list []string
+	for _, v := range h.Values(key) {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+func hopValue(values []string, i, hops int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if len(values) == hops {
+		return values[i]
+	}
+	return values[len(values)-1]
+}
+
+// parseForwarded parses the elements of an RFC 7239 Forwarded header.
+// Parameter names are case-insensitive and values may be tokens or quoted-strings.
+func parseForwarded(v string) []forwardedElement {
+	var elements []forwardedElement
+	var e forwardedElement
+	for i := 0; i <= len(v); {
+		if i == len(v) || v[i] == ',' {
+			if e != (forwardedElement{}) {
+				elements = append(elements, e)
+			}
+			e = forwardedElement{}
+			i++
+			continue
+		}
+		if v[i] == ';' || v[i] == ' ' || v[i] == '\t' {
+			i++
+			continue
+		}
+
+		// name=value
+		j := i
+		for j < len(v) && v[j] != '=' && v[j] != ';' && v[j] != ',' {
+			j++
+		}
+		name := strings.ToLower(strings.TrimSpace(v[i:j]))
+		if j == len(v) || v[j] != '=' {
+			i = j
+			continue
+		}
+		value, n := parseForwardedValue(v[j+1:])
+		i = j + 1 + n
+
+		switch name {
+		case "for":
+			e.forNode = value
+		case "proto":
+			e.proto = value
+		case "host":
+			e.host = value
+		}
+	}
+	return elements
+}
+
+// parseForwardedValue parses a token or quoted-string at the start of v
+// and returns it with the number of bytes consumed.
+func parseForwardedValue(v string) (string, int) {
+	if v == "" || v[0] != '"' {
+		i := 0
+		for i < len(v) && v[i] != ';' && v[i] != ',' {
+			i++
+		}
+		return strings.TrimSpace(v[:i]), i
+	}
+	var b strings.Builder
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			if i+1 < len(v) {
+				i++
+				b.WriteByte(v[i])
+			}
+		case '"':
+			return b.String(), i + 1
+		default:
+			b.WriteByte(v[i])
+		}
+	}
+	return b.String(), len(v)
+}
+
+// parseNode returns the IP address of a node such as "192.0.2.43", "192.0.2.43:47011",
+// "[2001:db8:cafe::17]" or "[2001:db8:cafe::17]:4711", or nil for "unknown",
+// obfuscated identifiers and invalid nodes.
+func parseNode(node string) net.IP {
+	node = strings.TrimSpace(node)
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+	node = strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+	return net.ParseIP(node)
+}
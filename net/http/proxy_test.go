@@ -0,0 +1,114 @@
+package http_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestTrustedProxyHeaders(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	tests := []struct {
+		name       string
+		opts       []handlers.ProxyOption
+		remoteAddr string
+		headers    map[string]string
+		wantAddr   string
+		wantScheme string
+		wantHost   string
+	}{
+		{
+			name:       "untrusted remote",
+			remoteAddr: "198.51.100.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.9"},
+			wantAddr:   "198.51.100.1:1234",
+		},
+		{
+			name:       "rightmost untrusted hop",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "127.0.0.1, 203.0.113.9, 10.0.0.2"},
+			wantAddr:   "203.0.113.9",
+		},
+		{
+			name:       "forwarded ignored by default",
+			remoteAddr: "10.0.0.1:1234",
+			headers: map[string]string{
+				"Forwarded":       "for=127.0.0.1;proto=https;host=evil.example",
+				"X-Forwarded-For": "127.0.0.1, 203.0.113.9",
+			},
+			wantAddr: "203.0.113.9",
+		},
+		{
+			name:       "forwarded alone ignored by default",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"Forwarded": "for=127.0.0.1"},
+			wantAddr:   "10.0.0.1:1234",
+		},
+		{
+			name:       "forwarded family",
+			opts:       []handlers.ProxyOption{handlers.TrustedHeaders(handlers.ForwardedHeader)},
+			remoteAddr: "10.0.0.1:1234",
+			headers: map[string]string{
+				"Forwarded":       `for=127.0.0.1, for="[2001:db8::1]:4711";proto=https;host=example.com`,
+				"X-Forwarded-For": "192.0.2.1",
+			},
+			wantAddr:   "2001:db8::1",
+			wantScheme: "https",
+			wantHost:   "example.com",
+		},
+		{
+			name:       "proto and host per hop",
+			remoteAddr: "10.0.0.1:1234",
+			headers: map[string]string{
+				"X-Forwarded-For":   "203.0.113.9",
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "example.com",
+			},
+			wantAddr:   "203.0.113.9",
+			wantScheme: "https",
+			wantHost:   "example.com",
+		},
+		{
+			name:       "ignore forwarded host",
+			opts:       []handlers.ProxyOption{handlers.IgnoreForwardedHost()},
+			remoteAddr: "10.0.0.1:1234",
+			headers: map[string]string{
+				"X-Forwarded-For":  "203.0.113.9",
+				"X-Forwarded-Host": "other.example",
+			},
+			wantAddr: "203.0.113.9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]handlers.ProxyOption{handlers.TrustedNetworks(proxies)}, tt.opts...)
+			var got *http.Request
+			h := handlers.TrustedProxyHeaders(opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got.RemoteAddr != tt.wantAddr {
+				t.Errorf("RemoteAddr = %q, want %q", got.RemoteAddr, tt.wantAddr)
+			}
+			if got.URL.Scheme != tt.wantScheme {
+				t.Errorf("Scheme = %q, want %q", got.URL.Scheme, tt.wantScheme)
+			}
+			wantHost := tt.wantHost
+			if wantHost == "" {
+				wantHost = "example.com"
+			}
+			if got.Host != wantHost {
+				t.Errorf("Host = %q, want %q", got.Host, wantHost)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit allows Requests requests per Window for each key.
+// Token bucket stores additionally allow bursts of up to Burst requests, which defaults to Requests.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
+	Burst    int
+}
+
+// RateLimitResult is the outcome of taking a request from a RateLimitStore.
+// Reset is the time until the limit is fully replenished, and RetryAfter the time
+// until the next request is allowed if this one was not.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore records the requests made under each key.
+// Implementations backed by shared storage allow limits across several servers.
+type RateLimitStore interface {
+	// Take records a request for key at time now under limit and reports whether it is allowed.
+	Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitOption configures RateLimiter.
+type RateLimitOption func(*rateLimiter)
+
+type rateLimiter struct {
+	limit    RateLimit
+	store    RateLimitStore
+	key      func(*http.Request) string
+	exceeded http.Handler
+}
+
+// RateLimiter returns middleware that limits the rate of requests per client.
+// Clients are identified by their IP address, as resolved by TrustedProxyHeaders earlier
+// in the chain, unless LimitByHeader or LimitByKey is given. Proxy headers taken from
+// untrusted clients defeat per-IP limiting, since each request can claim a new address,
+// so prefer TrustedProxyHeaders with the networks of your proxies over ProxyHeaders.
+// Requests are counted in a token bucket store in memory unless LimitStore is given.
+//
+// Every response carries RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and
+// RateLimit-Policy headers. Requests over the limit are answered with 429 Too Many
+// Requests and a Retry-After header. If the store fails, requests are let through.
+//
+// Example:
+//
+//  _, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+//  limit := handlers.RateLimiter(handlers.RateLimit{Requests: 100, Window: time.Minute},
+//  	handlers.LimitStore(handlers.NewSlidingWindowStore()))
+//  proxy := handlers.TrustedProxyHeaders(handlers.TrustedNetworks(proxies))
+//  http.ListenAndServe(":1123", handlers.NewChain(proxy, limit).Then(r))
+func RateLimiter(limit RateLimit, opts ...RateLimitOption) Middleware {
+	if limit.Requests <= 0 || limit.Window <= 0 {
+		panic("http: RateLimiter requires a positive number of requests and window")
+	}
+	rl := &rateLimiter{
+		limit: limit,
+		key:   clientIP,
+	}
+	for _, option := range opts {
+		option(rl)
+	}
+	if rl.store == nil {
+		rl.store = NewTokenBucketStore()
+	}
+	if rl.exceeded == nil {
+		rl.exceeded = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		})
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := rl.key(r)
+			if key == "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			result, err := rl.store.Take(key, rl.limit, time.Now())
+			if err != nil {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			hdr := w.Header()
+			hdr.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			hdr.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			hdr.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+			hdr.Set("RateLimit-Policy", strconv.Itoa(rl.limit.Requests)+";w="+strconv.Itoa(ceilSeconds(rl.limit.Window)))
+			if !result.Allowed {
+				hdr.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				rl.exceeded.ServeHTTP(w, r)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+//
+// Functional options for configuring RateLimiter.
+//
+
+// LimitStore sets the store requests are counted in.
+func LimitStore(store RateLimitStore) RateLimitOption {
+	return func(rl *rateLimiter) {
+		rl.store = store
+	}
+}
+
+// LimitByHeader identifies clients by the value of the named request header,
+// such as an API key, falling back to their IP address if it is missing.
+func LimitByHeader(name string) RateLimitOption {
+	return func(rl *rateLimiter) {
+		rl.key = func(r *http.Request) string {
+			if v := r.Header.Get(name); v != "" {
+				return name + ":" + v
+			}
+			return clientIP(r)
+		}
+	}
+}
+
+// LimitByKey identifies clients by the key returned by fn.
+// Requests for which fn returns "" are not limited.
+func LimitByKey(fn func(*http.Request) string) RateLimitOption {
+	return func(rl *rateLimiter) {
+		rl.key = fn
+	}
+}
+
+// LimitExceededHandler sets the handler serving requests over the limit.
+// The rate limit headers are set before it is called.
+func LimitExceededHandler(h http.Handler) RateLimitOption {
+	return func(rl *rateLimiter) {
+		rl.exceeded = h
+	}
+}
+
+// clientIP returns the IP address of the client of r, without the port.
+func clientIP(r *http.Request) string {
+	if ip := parseNode(r.RemoteAddr); ip != nil {
+		return ip.String()
+	}
+	return r.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimitStore is a RateLimitStore keeping its counters in memory,
+// created by NewTokenBucketStore or NewSlidingWindowStore.
+// Counters idle for longer than they take to reset are removed periodically.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	take    func(s *rateState, limit RateLimit, now time.Time) RateLimitResult
+	entries map[string]*rateState
+	takes   int
+}
+
+// rateState is the state of one key: the tokens of a bucket, or the counts of the
+// previous and current window starting at start.
+type rateState struct {
+	tokens   float64
+	start    time.Time
+	prev     int
+	curr     int
+	last     time.Time
+	lifetime time.Duration
+}
+
+// sweepInterval is the number of takes between removals of idle entries.
+const sweepInterval = 1024
+
+// NewTokenBucketStore returns a store limiting requests with a token bucket per key,
+// refilled at Requests per Window up to Burst tokens.
+func NewTokenBucketStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{take: takeToken, entries: make(map[string]*rateState)}
+}
+
+// NewSlidingWindowStore returns a store limiting requests with a sliding window counter
+// per key, which weights the count of the previous window by its overlap with the sliding window.
+func NewSlidingWindowStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{take: takeWindow, entries: make(map[string]*rateState)}
+}
+
+// Take is RateLimitStore.Take
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepInterval == 0 {
+		for k, e := range s.entries {
+			if now.Sub(e.last) > e.lifetime {
+				delete(s.entries, k)
+			}
+		}
+	}
+
+	e, ok := s.entries[key]
+	if !ok {
+		e = &rateState{tokens: -1}
+		s.entries[key] = e
+	}
+	e.lifetime = rateLifetime(limit)
+	result := s.take(e, limit, now)
+	e.last = now
+	return result, nil
+}
+
+// Len returns the number of keys tracked.
+func (s *MemoryRateLimitStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// rateLifetime returns how long the state of an idle key matters: the time a drained bucket
+// takes to refill completely, and at least the two windows a sliding window counter spans.
+func rateLifetime(limit RateLimit) time.Duration {
+	lifetime := 2 * limit.Window
+	if limit.Burst > limit.Requests {
+		if refill := time.Duration(float64(limit.Window) * float64(limit.Burst) / float64(limit.Requests)); refill > lifetime {
+			lifetime = refill
+		}
+	}
+	return lifetime
+}
+
+func takeToken(s *rateState, limit RateLimit, now time.Time) RateLimitResult {
+	capacity := float64(limit.Burst)
+	if limit.Burst <= 0 {
+		capacity = float64(limit.Requests)
+	}
+	rate := float64(limit.Requests) / limit.Window.Seconds()
+	if s.tokens < 0 {
+		s.tokens = capacity
+	} else {
+		s.tokens = math.Min(capacity, s.tokens+now.Sub(s.last).Seconds()*rate)
+	}
+
+	result := RateLimitResult{Limit: int(capacity)}
+	if s.tokens >= 1 {
+		s.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - s.tokens) / rate)
+	}
+	result.Remaining = int(s.tokens)
+	result.Reset = secondsDuration((capacity - s.tokens) / rate)
+	return result
+}
+
+func takeWindow(s *rateState, limit RateLimit, now time.Time) RateLimitResult {
+	if s.start.IsZero() {
+		s.start = now
+	}
+	if elapsed := now.Sub(s.start); elapsed >= limit.Window {
+		windows := elapsed / limit.Window
+		if windows == 1 {
+			s.prev = s.curr
+		} else {
+			s.prev = 0
+		}
+		s.curr = 0
+		s.start = s.start.Add(windows * limit.Window)
+	}
+	elapsed := now.Sub(s.start)
+	weight := 1 - float64(elapsed)/float64(limit.Window)
+	count := float64(s.prev)*weight + float64(s.curr)
+
+	result := RateLimitResult{Limit: limit.Requests, Reset: limit.Window - elapsed}
+	if count+1 <= float64(limit.Requests) {
+		s.curr++
+		count++
+		result.Allowed = true
+	} else {
+		// The weighted count of the previous window has to drop by the excess.
+		excess := count + 1 - float64(limit.Requests)
+		if s.prev > 0 && excess <= float64(s.prev)*weight {
+			result.RetryAfter = time.Duration(excess / float64(s.prev) * float64(limit.Window))
+		} else {
+			result.RetryAfter = limit.Window - elapsed
+		}
+	}
+	result.Remaining = int(math.Max(0, math.Floor(float64(limit.Requests)-count)))
+	if s.curr > 0 {
+		result.Reset += limit.Window
+	}
+	return result
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
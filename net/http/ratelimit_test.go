@@ -0,0 +1,213 @@
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+// rateStep is one request to a store at an offset from the start of a test.
+type rateStep struct {
+	at            time.Duration
+	wantAllowed   bool
+	wantRemaining int
+	wantRetry     time.Duration
+}
+
+func runRateSteps(t *testing.T, store handlers.RateLimitStore, limit handlers.RateLimit, steps []rateStep) {
+	t.Helper()
+	start := time.Unix(1700000000, 0)
+	for i, step := range steps {
+		result, err := store.Take("client", limit, start.Add(step.at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Allowed != step.wantAllowed || result.Remaining != step.wantRemaining || result.RetryAfter != step.wantRetry {
+			t.Errorf("step %d at %v: allowed %v, remaining %d, retry %v; want %v, %d, %v",
+				i, step.at, result.Allowed, result.Remaining, result.RetryAfter, step.wantAllowed, step.wantRemaining, step.wantRetry)
+		}
+	}
+}
+
+func TestTokenBucketStore(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit handlers.RateLimit
+		steps []rateStep
+	}{
+		{
+			name:  "burst then refill",
+			limit: handlers.RateLimit{Requests: 1, Window: time.Second, Burst: 3},
+			steps: []rateStep{
+				{0, true, 2, 0},
+				{0, true, 1, 0},
+				{0, true, 0, 0},
+				{0, false, 0, time.Second},
+				{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+				{time.Second, true, 0, 0},
+				// Many windows later the bucket is full again, but not beyond its burst.
+				{10 * time.Second, true, 2, 0},
+			},
+		},
+		{
+			name:  "burst defaults to requests",
+			limit: handlers.RateLimit{Requests: 2, Window: 10 * time.Second},
+			steps: []rateStep{
+				{0, true, 1, 0},
+				{0, true, 0, 0},
+				{0, false, 0, 5 * time.Second},
+				{5 * time.Second, true, 0, 0},
+				{10 * time.Second, true, 0, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runRateSteps(t, handlers.NewTokenBucketStore(), tt.limit, tt.steps)
+		})
+	}
+}
+
+func TestSlidingWindowStore(t *testing.T) {
+	limit := handlers.RateLimit{Requests: 2, Window: 10 * time.Second}
+	runRateSteps(t, handlers.NewSlidingWindowStore(), limit, []rateStep{
+		{0, true, 1, 0},
+		{0, true, 0, 0},
+		{time.Second, false, 0, 9 * time.Second},
+		// At the window boundary the previous window still counts fully.
+		{10 * time.Second, false, 0, 5 * time.Second},
+		// Halfway through, half of it is left.
+		{15 * time.Second, true, 0, 0},
+		{15 * time.Second, false, 0, 5 * time.Second},
+		// After more than one elapsed window nothing is carried over.
+		{35 * time.Second, true, 1, 0},
+	})
+}
+
+func TestMemoryRateLimitStoreSweep(t *testing.T) {
+	const sweepInterval = 1024
+	tests := []struct {
+		name          string
+		store         *handlers.MemoryRateLimitStore
+		limit         handlers.RateLimit
+		idle          time.Duration
+		wantRemaining int
+	}{
+		// A bucket refilling for 10s must survive 5s of idleness with 5 tokens.
+		{"partly drained bucket kept", handlers.NewTokenBucketStore(), handlers.RateLimit{Requests: 1, Window: time.Second, Burst: 10}, 5 * time.Second, 4},
+		{"full bucket removed", handlers.NewTokenBucketStore(), handlers.RateLimit{Requests: 1, Window: time.Second, Burst: 10}, 11 * time.Second, 9},
+		{"window kept", handlers.NewSlidingWindowStore(), handlers.RateLimit{Requests: 10, Window: 10 * time.Second}, 15 * time.Second, 4},
+		{"window removed", handlers.NewSlidingWindowStore(), handlers.RateLimit{Requests: 10, Window: 10 * time.Second}, 21 * time.Second, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Unix(1700000000, 0)
+			for i := 0; i < 10; i++ {
+				tt.store.Take("a", tt.limit, start)
+			}
+			later := start.Add(tt.idle)
+			for i := 10; i < sweepInterval-1; i++ {
+				tt.store.Take("b", tt.limit, later)
+			}
+			// This take sweeps the idle entries before counting.
+			result, _ := tt.store.Take("a", tt.limit, later)
+			if result.Remaining != tt.wantRemaining {
+				t.Errorf("remaining = %d, want %d", result.Remaining, tt.wantRemaining)
+			}
+			if tt.store.Len() != 2 {
+				t.Errorf("Len = %d, want 2", tt.store.Len())
+			}
+		})
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(string, handlers.RateLimit, time.Time) (handlers.RateLimitResult, error) {
+	return handlers.RateLimitResult{}, errors.New("store down")
+}
+
+func TestRateLimiter(t *testing.T) {
+	limit := handlers.RateLimit{Requests: 2, Window: time.Minute}
+	tests := []struct {
+		name        string
+		opts        []handlers.RateLimitOption
+		requests    int
+		header      string
+		wantCode    int
+		wantHeaders map[string]string
+	}{
+		{
+			name:     "allowed",
+			requests: 1,
+			wantCode: http.StatusOK,
+			wantHeaders: map[string]string{
+				"RateLimit-Limit":     "2",
+				"RateLimit-Remaining": "1",
+				"RateLimit-Reset":     "30",
+				"RateLimit-Policy":    "2;w=60",
+				"Retry-After":         "",
+			},
+		},
+		{
+			name:     "exceeded",
+			requests: 3,
+			wantCode: http.StatusTooManyRequests,
+			wantHeaders: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+				"Retry-After":         "30",
+			},
+		},
+		{
+			name:        "fails open",
+			opts:        []handlers.RateLimitOption{handlers.LimitStore(failingStore{})},
+			requests:    3,
+			wantCode:    http.StatusOK,
+			wantHeaders: map[string]string{"RateLimit-Limit": "", "Retry-After": ""},
+		},
+		{
+			name:        "by header",
+			opts:        []handlers.RateLimitOption{handlers.LimitByHeader("X-API-Key")},
+			requests:    3,
+			header:      "per-request",
+			wantCode:    http.StatusOK,
+			wantHeaders: map[string]string{"RateLimit-Remaining": "1"},
+		},
+		{
+			name:        "unlimited key",
+			opts:        []handlers.RateLimitOption{handlers.LimitByKey(func(*http.Request) string { return "" })},
+			requests:    3,
+			wantCode:    http.StatusOK,
+			wantHeaders: map[string]string{"RateLimit-Limit": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handlers.RateLimiter(limit, tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			var rec *httptest.ResponseRecorder
+			for i := 0; i < tt.requests; i++ {
+				req := httptest.NewRequest("GET", "/", nil)
+				req.RemoteAddr = "192.0.2.1:1234"
+				if tt.header != "" {
+					req.Header.Set("X-API-Key", tt.header+strconv.Itoa(i))
+				}
+				rec = httptest.NewRecorder()
+				h.ServeHTTP(rec, req)
+			}
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			for k, want := range tt.wantHeaders {
+				if got := rec.Header().Get(k); got != want {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
@@ -32,7 +32,8 @@ func parseRecoveryOptions(h http.Handler, opts ...RecoveryOption) http.Handler {
 
 // RecoveryHandler is HTTP middleware that recovers from a panic,
 // logs the panic, writes http.StatusInternalServerError, and
-// continues to the next handler.
+// continues to the next handler. The request and trace IDs set by
+// the RequestID middleware are logged with the panic.
 //
 // Example:
 //
@@ -68,9 +69,14 @@ func PrintRecoveryStack(print bool) RecoveryOption {
 }
 
 func (h recoveryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	req, _ = withCorrelation(req)
 	defer func() {
 		if err := recover(); err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
+			if id, traceID := requestCorrelation(req, w.Header()); id != "" || traceID != "" {
+				h.log("request_id="+id, "trace_id="+traceID, err)
+				return
+			}
 			h.log(err)
 		}
 	}()
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	// RequestIDHeader is the header carrying the request ID.
+	RequestIDHeader = "X-Request-ID"
+	// TraceParentHeader is the W3C Trace Context header identifying the trace and the calling span.
+	TraceParentHeader = "traceparent"
+	// TraceStateHeader is the W3C Trace Context header carrying vendor-specific trace state.
+	TraceStateHeader = "tracestate"
+
+	// maxRequestIDLength bounds the length of incoming request IDs that are accepted.
+	maxRequestIDLength = 200
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	traceContextKey
+	correlationKey
+)
+
+// correlation is filled in by RequestID for the handlers further up the chain,
+// such as the logging and recovery handlers, which do not see its request context.
+type correlation struct {
+	header  string
+	id      string
+	traceID string
+}
+
+// withCorrelation returns req with a correlation for RequestID to fill in,
+// unless its context already holds one.
+func withCorrelation(req *http.Request) (*http.Request, *correlation) {
+	if c, ok := req.Context().Value(correlationKey).(*correlation); ok {
+		return req, c
+	}
+	c := &correlation{}
+	return req.WithContext(context.WithValue(req.Context(), correlationKey, c)), c
+}
+
+// TraceContext is the W3C Trace Context of a request.
+// TraceID identifies the whole trace, SpanID the span of the current request,
+// and ParentID the span of the caller, if the request carried a valid traceparent.
+type TraceContext struct {
+	TraceID  string
+	SpanID   string
+	ParentID string
+	Flags    string
+	State    string
+}
+
+// TraceParent returns the traceparent header value for the span of the current request.
+func (tc TraceContext) TraceParent() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.Flags
+}
+
+// RequestIDOption configures RequestID.
+type RequestIDOption func(*requestID)
+
+type requestID struct {
+	header        string
+	generate      func() string
+	trustIncoming bool
+}
+
+// RequestID returns middleware that reads the request ID from the X-Request-ID header,
+// or generates one, and reads the W3C traceparent and tracestate headers, starting a new
+// trace if they are missing or invalid. A new span ID is generated for every request.
+//
+// The values are stored in the request context, set on the request headers for
+// downstream handlers and echoed in the response headers. LogFormatterParams and
+// the panics logged by RecoveryHandler include them.
+//
+// Example:
+//
+//  chain := handlers.NewChain(handlers.RecoveryHandler(), handlers.Logging(os.Stdout), handlers.RequestID())
+//  http.ListenAndServe(":1123", chain.Then(r))
+func RequestID(opts ...RequestIDOption) Middleware {
+	rid := &requestID{
+		header:        RequestIDHeader,
+		generate:      newRequestID,
+		trustIncoming: true,
+	}
+	for _, option := range opts {
+		option(rid)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := ""
+			if rid.trustIncoming {
+				id = r.Header.Get(rid.header)
+			}
+			if !validRequestID(id) {
+				id = rid.generate()
+			}
+
+			tc, ok := parseTraceParent(r.Header.Get(TraceParentHeader))
+			if ok {
+				tc.State = r.Header.Get(TraceStateHeader)
+			} else {
+				tc = TraceContext{TraceID: randomHex(16), Flags: "00"}
+			}
+			tc.SpanID = randomHex(8)
+
+			r.Header.Set(rid.header, id)
+			r.Header.Set(TraceParentHeader, tc.TraceParent())
+			w.Header().Set(rid.header, id)
+			w.Header().Set(TraceParentHeader, tc.TraceParent())
+			if tc.State != "" {
+				w.Header().Set(TraceStateHeader, tc.State)
+			}
+
+			if c, ok := r.Context().Value(correlationKey).(*correlation); ok {
+				c.header, c.id, c.traceID = rid.header, id, tc.TraceID
+			}
+			ctx := context.WithValue(r.Context(), requestIDKey, id)
+			ctx = context.WithValue(ctx, traceContextKey, tc)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDHeaderName sets the header the request ID is read from and echoed in.
+func RequestIDHeaderName(name string) RequestIDOption {
+	return func(rid *requestID) {
+		rid.header = http.CanonicalHeaderKey(name)
+	}
+}
+
+// RequestIDGenerator sets the function generating request IDs.
+// By default, request IDs are 32 random hexadecimal digits.
+func RequestIDGenerator(fn func() string) RequestIDOption {
+	return func(rid *requestID) {
+		rid.generate = fn
+	}
+}
+
+// IgnoreIncomingRequestID always generates a new request ID, ignoring the one sent by the client.
+func IgnoreIncomingRequestID() RequestIDOption {
+	return func(rid *requestID) {
+		rid.trustIncoming = false
+	}
+}
+
+// RequestIDFromContext returns the request ID stored by RequestID, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// TraceFromContext returns the trace context stored by RequestID.
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey).(TraceContext)
+	return tc, ok
+}
+
+// requestCorrelation returns the request ID and trace ID of req, from its context,
+// or else as filled in by RequestID further down the handler chain, or else from the
+// response header h.
+func requestCorrelation(req *http.Request, h http.Header) (id, traceID string) {
+	id = RequestIDFromContext(req.Context())
+	if tc, ok := TraceFromContext(req.Context()); ok {
+		traceID = tc.TraceID
+	}
+	header := RequestIDHeader
+	if c, ok := req.Context().Value(correlationKey).(*correlation); ok && c.header != "" {
+		header = c.header
+		if id == "" {
+			id = c.id
+		}
+		if traceID == "" {
+			traceID = c.traceID
+		}
+	}
+	if h == nil {
+		return id, traceID
+	}
+	if id == "" {
+		id = h.Get(header)
+	}
+	if traceID == "" {
+		if tc, ok := parseTraceParent(h.Get(TraceParentHeader)); ok {
+			traceID = tc.TraceID
+		}
+	}
+	return id, traceID
+}
+
+// parseTraceParent parses a version 00 traceparent header value.
+// The returned SpanID is empty and ParentID holds the caller's span.
+func parseTraceParent(v string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || !isLowerHex(parts[0]) {
+		return TraceContext{}, false
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return TraceContext{}, false
+	}
+	traceID, parentID, flags := parts[1], parts[2], parts[3]
+	if len(traceID) != 32 || !isLowerHex(traceID) || traceID == strings.Repeat("0", 32) {
+		return TraceContext{}, false
+	}
+	if len(parentID) != 16 || !isLowerHex(parentID) || parentID == strings.Repeat("0", 16) {
+		return TraceContext{}, false
+	}
+	if len(flags) != 2 || !isLowerHex(flags) {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: traceID, ParentID: parentID, Flags: flags}, true
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII characters up to maxRequestIDLength.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	return randomHex(16)
+}
+
+// randomHex returns n random bytes in hexadecimal.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
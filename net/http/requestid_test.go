@@ -0,0 +1,172 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestRequestID(t *testing.T) {
+	generate := handlers.RequestIDGenerator(func() string { return "generated" })
+	tests := []struct {
+		name     string
+		opts     []handlers.RequestIDOption
+		header   string
+		incoming string
+		want     string
+	}{
+		{name: "generated", want: "generated"},
+		{name: "propagated", incoming: "abc-123", want: "abc-123"},
+		{name: "invalid characters", incoming: "abc 123", want: "generated"},
+		{name: "too long", incoming: strings.Repeat("a", 201), want: "generated"},
+		{name: "longest", incoming: strings.Repeat("a", 200), want: strings.Repeat("a", 200)},
+		{name: "ignored", opts: []handlers.RequestIDOption{handlers.IgnoreIncomingRequestID()}, incoming: "abc-123", want: "generated"},
+		{name: "header name", opts: []handlers.RequestIDOption{handlers.RequestIDHeaderName("x-correlation-id")}, header: "X-Correlation-Id", incoming: "abc-123", want: "abc-123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == "" {
+				header = handlers.RequestIDHeader
+			}
+			var fromContext, fromHeader string
+			h := handlers.RequestID(append([]handlers.RequestIDOption{generate}, tt.opts...)...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fromContext = handlers.RequestIDFromContext(r.Context())
+				fromHeader = r.Header.Get(header)
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(header, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if fromContext != tt.want || fromHeader != tt.want {
+				t.Errorf("request ID = %q in context, %q in header; want %q", fromContext, fromHeader, tt.want)
+			}
+			if got := rec.Header().Get(header); got != tt.want {
+				t.Errorf("response %s = %q, want %q", header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestIDTraceParent(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name        string
+		traceparent string
+		propagated  bool
+	}{
+		{"valid", "00-" + traceID + "-" + parentID + "-01", true},
+		{"surrounding spaces", " 00-" + traceID + "-" + parentID + "-01 ", true},
+		{"future version", "cc-" + traceID + "-" + parentID + "-01-extra", true},
+		{"missing", "", false},
+		{"version ff", "ff-" + traceID + "-" + parentID + "-01", false},
+		{"version 00 with extra field", "00-" + traceID + "-" + parentID + "-01-extra", false},
+		{"uppercase version", "0A-" + traceID + "-" + parentID + "-01", false},
+		{"zero trace ID", "00-" + strings.Repeat("0", 32) + "-" + parentID + "-01", false},
+		{"zero parent ID", "00-" + traceID + "-" + strings.Repeat("0", 16) + "-01", false},
+		{"short trace ID", "00-" + traceID[1:] + "-" + parentID + "-01", false},
+		{"long parent ID", "00-" + traceID + "-" + parentID + "0-01", false},
+		{"short flags", "00-" + traceID + "-" + parentID + "-1", false},
+		{"uppercase trace ID", "00-" + strings.ToUpper(traceID) + "-" + parentID + "-01", false},
+		{"uppercase parent ID", "00-" + traceID + "-" + strings.ToUpper(parentID) + "-01", false},
+		{"too few fields", "00-" + traceID + "-" + parentID, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tc handlers.TraceContext
+			h := handlers.RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tc, _ = handlers.TraceFromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.traceparent != "" {
+				req.Header.Set(handlers.TraceParentHeader, tt.traceparent)
+			}
+			req.Header.Set(handlers.TraceStateHeader, "vendor=value")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if len(tc.TraceID) != 32 || len(tc.SpanID) != 16 || tc.SpanID == parentID {
+				t.Fatalf("trace context = %+v", tc)
+			}
+			if got := rec.Header().Get(handlers.TraceParentHeader); got != tc.TraceParent() {
+				t.Errorf("response traceparent = %q, want %q", got, tc.TraceParent())
+			}
+			if tt.propagated {
+				if tc.TraceID != traceID || tc.ParentID != parentID || tc.Flags != "01" || tc.State != "vendor=value" {
+					t.Errorf("trace context = %+v, want the propagated trace", tc)
+				}
+				if got := rec.Header().Get(handlers.TraceStateHeader); got != "vendor=value" {
+					t.Errorf("response tracestate = %q", got)
+				}
+				return
+			}
+			if tc.TraceID == traceID || tc.ParentID != "" || tc.Flags != "00" || tc.State != "" {
+				t.Errorf("trace context = %+v, want a new trace", tc)
+			}
+			if got := rec.Header().Get(handlers.TraceStateHeader); got != "" {
+				t.Errorf("response tracestate = %q, want none", got)
+			}
+		})
+	}
+}
+
+type printlnLogger struct {
+	bytes.Buffer
+}
+
+func (l *printlnLogger) Println(v ...interface{}) {
+	fmt.Fprintln(&l.Buffer, v...)
+}
+
+func TestRequestIDCorrelation(t *testing.T) {
+	requestID := handlers.RequestID(
+		handlers.RequestIDHeaderName("X-Correlation-ID"),
+		handlers.RequestIDGenerator(func() string { return "generated" }),
+	)
+	var traceID string
+	app := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc, _ := handlers.TraceFromContext(r.Context())
+		traceID = tc.TraceID
+		if r.URL.Path == "/panic" {
+			panic("boom")
+		}
+	})
+	var logs bytes.Buffer
+	recoveryLogs := &printlnLogger{}
+	h := handlers.NewChain(
+		func(h http.Handler) http.Handler { return handlers.JSONLoggingHandler(&logs, h) },
+		handlers.RecoveryHandler(handlers.RecoveryLogger(recoveryLogs)),
+		requestID,
+	).Then(app)
+
+	for _, path := range []string{"/", "/panic"} {
+		logs.Reset()
+		req := httptest.NewRequest("GET", path, nil)
+		// Only the configured header is trusted.
+		req.Header.Set(handlers.RequestIDHeader, "ignored")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal(logs.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: %v in %q", path, err, logs.String())
+		}
+		if entry["request_id"] != "generated" || entry["trace_id"] != traceID {
+			t.Errorf("%s: logged request_id %v, trace_id %v; want generated, %s", path, entry["request_id"], entry["trace_id"], traceID)
+		}
+	}
+	if want := "request_id=generated trace_id=" + traceID + " boom\n"; recoveryLogs.String() != want {
+		t.Errorf("recovery log = %q, want %q", recoveryLogs.String(), want)
+	}
+}
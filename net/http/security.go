@@ -0,0 +1,192 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	hstsHeader              = "Strict-Transport-Security"
+	cspHeader               = "Content-Security-Policy"
+	cspReportOnlyHeader     = "Content-Security-Policy-Report-Only"
+	contentTypeOptionHeader = "X-Content-Type-Options"
+	frameOptionsHeader      = "X-Frame-Options"
+	referrerPolicyHeader    = "Referrer-Policy"
+	permissionsPolicyHeader = "Permissions-Policy"
+
+	// CSPNoncePlaceholder is replaced by the nonce of each request in a Content-Security-Policy.
+	CSPNoncePlaceholder = "{nonce}"
+)
+
+// SecurityOption configures SecurityHeaders.
+type SecurityOption func(*securityHeaders)
+
+type securityHeaders struct {
+	hsts              string
+	csp               string
+	cspReportOnly     bool
+	contentTypeOption string
+	frameOptions      string
+	referrerPolicy    string
+	permissionsPolicy string
+}
+
+type cspNonceKey struct{}
+
+// SecurityHeaders returns middleware that sets security headers on every response.
+// By default it sends
+//
+//  Strict-Transport-Security: max-age=63072000; includeSubDomains
+//  X-Content-Type-Options: nosniff
+//  X-Frame-Options: DENY
+//  Referrer-Policy: strict-origin-when-cross-origin
+//
+// with Strict-Transport-Security only on HTTPS requests, including those marked as
+// such by ProxyHeaders or TrustedProxyHeaders. Handlers may override any of them.
+//
+// If the Content-Security-Policy contains CSPNoncePlaceholder, a random nonce is generated
+// for each request and substituted in the policy. Templates include it with the cspNonce
+// function of TemplateFuncMap, or handlers read it with CSPNonceFromContext.
+//
+// Example:
+//
+//  secure := handlers.SecurityHeaders(
+//  	handlers.ContentSecurityPolicy("default-src 'self'; script-src 'self' 'nonce-{nonce}'"),
+//  	handlers.PermissionsPolicy("camera=(), geolocation=()"),
+//  )
+//  http.ListenAndServe(":1123", secure(r))
+//
+// and in a template parsed with TemplateFuncMap(nil):
+//
+//  <script nonce="{{cspNonce}}">...</script>
+func SecurityHeaders(opts ...SecurityOption) Middleware {
+	s := &securityHeaders{
+		hsts:              "max-age=63072000; includeSubDomains",
+		contentTypeOption: "nosniff",
+		frameOptions:      "DENY",
+		referrerPolicy:    "strict-origin-when-cross-origin",
+	}
+	for _, option := range opts {
+		option(s)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hdr := w.Header()
+			if s.hsts != "" && (r.TLS != nil || r.URL.Scheme == "https") {
+				hdr.Set(hstsHeader, s.hsts)
+			}
+			if s.csp != "" {
+				policy := s.csp
+				if strings.Contains(policy, CSPNoncePlaceholder) {
+					nonce := newCSPNonce()
+					policy = strings.Replace(policy, CSPNoncePlaceholder, nonce, -1)
+					r = r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, nonce))
+				}
+				if s.cspReportOnly {
+					hdr.Set(cspReportOnlyHeader, policy)
+				} else {
+					hdr.Set(cspHeader, policy)
+				}
+			}
+			setHeader(hdr, contentTypeOptionHeader, s.contentTypeOption)
+			setHeader(hdr, frameOptionsHeader, s.frameOptions)
+			setHeader(hdr, referrerPolicyHeader, s.referrerPolicy)
+			setHeader(hdr, permissionsPolicyHeader, s.permissionsPolicy)
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+//
+// Functional options for configuring SecurityHeaders.
+//
+
+// HSTS sets the Strict-Transport-Security max-age and flags.
+// A maxAge of 0 or less disables the header.
+func HSTS(maxAge time.Duration, includeSubDomains, preload bool) SecurityOption {
+	return func(s *securityHeaders) {
+		if maxAge <= 0 {
+			s.hsts = ""
+			return
+		}
+		s.hsts = "max-age=" + strconv.FormatInt(int64(maxAge/time.Second), 10)
+		if includeSubDomains {
+			s.hsts += "; includeSubDomains"
+		}
+		if preload {
+			s.hsts += "; preload"
+		}
+	}
+}
+
+// ContentSecurityPolicy sets the Content-Security-Policy, in which every
+// CSPNoncePlaceholder is replaced by the nonce of the request.
+func ContentSecurityPolicy(policy string) SecurityOption {
+	return func(s *securityHeaders) {
+		s.csp = policy
+	}
+}
+
+// CSPReportOnly sends the policy in Content-Security-Policy-Report-Only instead,
+// so that browsers report violations without enforcing it.
+func CSPReportOnly() SecurityOption {
+	return func(s *securityHeaders) {
+		s.cspReportOnly = true
+	}
+}
+
+// ContentTypeOptions sets X-Content-Type-Options. An empty value disables the header.
+func ContentTypeOptions(value string) SecurityOption {
+	return func(s *securityHeaders) {
+		s.contentTypeOption = value
+	}
+}
+
+// FrameOptions sets X-Frame-Options, such as "DENY" or "SAMEORIGIN".
+// An empty value disables the header.
+func FrameOptions(value string) SecurityOption {
+	return func(s *securityHeaders) {
+		s.frameOptions = value
+	}
+}
+
+// ReferrerPolicy sets Referrer-Policy. An empty value disables the header.
+func ReferrerPolicy(value string) SecurityOption {
+	return func(s *securityHeaders) {
+		s.referrerPolicy = value
+	}
+}
+
+// PermissionsPolicy sets Permissions-Policy, such as "camera=(), microphone=()".
+func PermissionsPolicy(value string) SecurityOption {
+	return func(s *securityHeaders) {
+		s.permissionsPolicy = value
+	}
+}
+
+// CSPNonceFromContext returns the Content-Security-Policy nonce of the request,
+// or "" if SecurityHeaders did not generate one.
+func CSPNonceFromContext(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// setHeader sets a header unless value is empty.
+func setHeader(hdr http.Header, key, value string) {
+	if value != "" {
+		hdr.Set(key, value)
+	}
+}
+
+func newCSPNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
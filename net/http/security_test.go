@@ -0,0 +1,137 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []handlers.SecurityOption
+		https   bool
+		handler func(w http.ResponseWriter)
+		want    map[string]string
+	}{
+		{
+			name: "defaults over http",
+			want: map[string]string{
+				"Strict-Transport-Security": "",
+				"X-Content-Type-Options":    "nosniff",
+				"X-Frame-Options":           "DENY",
+				"Referrer-Policy":           "strict-origin-when-cross-origin",
+				"Content-Security-Policy":   "",
+				"Permissions-Policy":        "",
+			},
+		},
+		{
+			name:  "defaults over https",
+			https: true,
+			want:  map[string]string{"Strict-Transport-Security": "max-age=63072000; includeSubDomains"},
+		},
+		{
+			name:  "hsts preload",
+			opts:  []handlers.SecurityOption{handlers.HSTS(365*24*time.Hour, false, true)},
+			https: true,
+			want:  map[string]string{"Strict-Transport-Security": "max-age=31536000; preload"},
+		},
+		{
+			name:  "hsts disabled",
+			opts:  []handlers.SecurityOption{handlers.HSTS(0, true, true)},
+			https: true,
+			want:  map[string]string{"Strict-Transport-Security": ""},
+		},
+		{
+			name: "headers disabled",
+			opts: []handlers.SecurityOption{handlers.ContentTypeOptions(""), handlers.FrameOptions(""), handlers.ReferrerPolicy("")},
+			want: map[string]string{"X-Content-Type-Options": "", "X-Frame-Options": "", "Referrer-Policy": ""},
+		},
+		{
+			name: "custom values",
+			opts: []handlers.SecurityOption{
+				handlers.FrameOptions("SAMEORIGIN"),
+				handlers.ReferrerPolicy("no-referrer"),
+				handlers.PermissionsPolicy("camera=()"),
+				handlers.ContentSecurityPolicy("default-src 'self'"),
+			},
+			want: map[string]string{
+				"X-Frame-Options":         "SAMEORIGIN",
+				"Referrer-Policy":         "no-referrer",
+				"Permissions-Policy":      "camera=()",
+				"Content-Security-Policy": "default-src 'self'",
+			},
+		},
+		{
+			name: "report only",
+			opts: []handlers.SecurityOption{handlers.ContentSecurityPolicy("default-src 'self'"), handlers.CSPReportOnly()},
+			want: map[string]string{
+				"Content-Security-Policy":             "",
+				"Content-Security-Policy-Report-Only": "default-src 'self'",
+			},
+		},
+		{
+			name:    "handler overrides",
+			handler: func(w http.ResponseWriter) { w.Header().Set("X-Frame-Options", "SAMEORIGIN") },
+			want:    map[string]string{"X-Frame-Options": "SAMEORIGIN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handlers.SecurityHeaders(tt.opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.handler != nil {
+					tt.handler(w)
+				}
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.https {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			for k, want := range tt.want {
+				if got := rec.Header().Get(k); got != want {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersCSPNonce(t *testing.T) {
+	page := template.Must(template.New("page").Funcs(handlers.TemplateFuncMap(nil)).Parse(`<script nonce="{{cspNonce}}"></script>`))
+	h := handlers.SecurityHeaders(
+		handlers.ContentSecurityPolicy("script-src 'nonce-{nonce}'; style-src 'nonce-{nonce}'"),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		template.Must(page.Clone()).Funcs(handlers.TemplateFuncMap(r)).Execute(w, nil)
+	}))
+
+	nonces := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		policy := rec.Header().Get("Content-Security-Policy")
+		nonce, _, _ := strings.Cut(strings.TrimPrefix(policy, "script-src 'nonce-"), "'")
+		if nonce == "" || strings.Contains(policy, handlers.CSPNoncePlaceholder) {
+			t.Fatalf("policy %q has no nonce", policy)
+		}
+		if want := "script-src 'nonce-" + nonce + "'; style-src 'nonce-" + nonce + "'"; policy != want {
+			t.Errorf("policy = %q, want %q", policy, want)
+		}
+		if want := `<script nonce="` + nonce + `"></script>`; rec.Body.String() != want {
+			t.Errorf("body = %q, want %q", rec.Body.String(), want)
+		}
+		nonces[nonce] = true
+	}
+	if len(nonces) != 2 {
+		t.Error("nonce is reused across requests")
+	}
+}
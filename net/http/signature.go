@@ -0,0 +1,212 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofunct/functional/crypto"
+)
+
+const (
+	// SignatureTimestampHeader carries the Unix time in seconds at which a request was signed.
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+
+	hmacScheme = "HMAC-SHA256"
+)
+
+const (
+	// DefaultSignatureSkew is the default maximum age of signed requests, in either direction.
+	DefaultSignatureSkew = 5 * time.Minute
+	// DefaultMaxSignedBodySize is the default limit for the bodies of signed requests.
+	DefaultMaxSignedBodySize = 10 << 20
+)
+
+// HMACKeyFunc returns the secret of the key with the given ID, and whether it exists.
+type HMACKeyFunc func(keyID string) ([]byte, bool)
+
+// HMACKeys returns an HMACKeyFunc looking up secrets by key ID in keys.
+func HMACKeys(keys map[string][]byte) HMACKeyFunc {
+	return func(keyID string) ([]byte, bool) {
+		secret, ok := keys[keyID]
+		return secret, ok
+	}
+}
+
+// HMACOption configures HMACAuth.
+type HMACOption func(*hmacAuth)
+
+type hmacAuth struct {
+	keys        HMACKeyFunc
+	maxSkew     time.Duration
+	maxBodySize int64
+}
+
+// HMACAuth returns middleware that verifies requests signed with SignRequest, answering
+// requests with a missing, unknown or invalid signature with 401 Unauthorized.
+// Requests signed more than DefaultSignatureSkew before or after the server time are
+// rejected, limiting replays. The body is read to verify its hash only after the key
+// and timestamp are checked, and bodies larger than DefaultMaxSignedBodySize are
+// answered with 413 Request Entity Too Large.
+// The key ID is stored as the name of the Principal of the request.
+//
+// The signature is the hex-encoded HMAC-SHA256 of the method, request URI,
+// timestamp and hex-encoded SHA-256 of the body, separated by newlines, sent as
+//
+//  Authorization: HMAC-SHA256 keyId="client-1", signature="9f86d0..."
+//  X-Signature-Timestamp: 1700000000
+//
+// Example:
+//
+//  keys := handlers.HMACKeys(map[string][]byte{"client-1": secret})
+//  http.Handle("/hooks", handlers.HMACAuth(keys, handlers.HMACMaxBodySize(1<<20))(hooksHandler))
+func HMACAuth(keys HMACKeyFunc, opts ...HMACOption) Middleware {
+	a := &hmacAuth{
+		keys:        keys,
+		maxSkew:     DefaultSignatureSkew,
+		maxBodySize: DefaultMaxSignedBodySize,
+	}
+	for _, option := range opts {
+		option(a)
+	}
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyID, err := a.verify(w, r, time.Now())
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", hmacScheme)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			p := &Principal{Name: keyID, Scheme: AuthSchemeHMAC}
+			h.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+//
+// Functional options for configuring HMACAuth.
+//
+
+// HMACMaxSkew sets the maximum age of signed requests, in either direction.
+func HMACMaxSkew(maxSkew time.Duration) HMACOption {
+	return func(a *hmacAuth) {
+		if maxSkew > 0 {
+			a.maxSkew = maxSkew
+		}
+	}
+}
+
+// HMACMaxBodySize sets the maximum size of the bodies of signed requests in bytes.
+func HMACMaxBodySize(size int64) HMACOption {
+	return func(a *hmacAuth) {
+		if size > 0 {
+			a.maxBodySize = size
+		}
+	}
+}
+
+// SignRequest signs r with the key keyID and its secret for verification by HMACAuth,
+// setting the Authorization and X-Signature-Timestamp headers. The body of r is read
+// and replaced, so it should be called after the body is set.
+func SignRequest(r *http.Request, keyID string, secret []byte) error {
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := crypto.HmacSha256(secret, canonicalRequest(r, timestamp, body))
+	r.Header.Set(SignatureTimestampHeader, timestamp)
+	r.Header.Set("Authorization", fmt.Sprintf(`%s keyId=%q, signature="%s"`, hmacScheme, keyID, hex.EncodeToString(signature)))
+	return nil
+}
+
+// verify checks the signature of r and returns its key ID.
+func (a *hmacAuth) verify(w http.ResponseWriter, r *http.Request, now time.Time) (string, error) {
+	keyID, signature, err := parseSignature(r.Header.Get("Authorization"))
+	if err != nil {
+		return "", err
+	}
+	timestamp := r.Header.Get(SignatureTimestampHeader)
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", ErrInvalidCredentials
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > a.maxSkew || skew < -a.maxSkew {
+		return "", ErrInvalidCredentials
+	}
+	secret, ok := a.keys(keyID)
+	if !ok {
+		return "", ErrInvalidCredentials
+	}
+	if r.ContentLength > a.maxBodySize {
+		return "", &http.MaxBytesError{Limit: a.maxBodySize}
+	}
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = http.MaxBytesReader(w, r.Body, a.maxBodySize)
+	}
+	body, err := readBody(r)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal(signature, crypto.HmacSha256(secret, canonicalRequest(r, timestamp, body))) {
+		return "", ErrInvalidCredentials
+	}
+	return keyID, nil
+}
+
+// parseSignature parses an "Authorization: HMAC-SHA256" header.
+func parseSignature(auth string) (string, []byte, error) {
+	if len(auth) <= len(hmacScheme) || !strings.EqualFold(auth[:len(hmacScheme)], hmacScheme) || auth[len(hmacScheme)] != ' ' {
+		return "", nil, ErrInvalidCredentials
+	}
+	var keyID, signature string
+	for _, param := range strings.Split(auth[len(hmacScheme)+1:], ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(name) {
+		case "keyid":
+			keyID = value
+		case "signature":
+			signature = value
+		}
+	}
+	sig, err := hex.DecodeString(signature)
+	if keyID == "" || err != nil || len(sig) == 0 {
+		return "", nil, ErrInvalidCredentials
+	}
+	return keyID, sig, nil
+}
+
+// canonicalRequest returns the string signed for r.
+func canonicalRequest(r *http.Request, timestamp string, body []byte) []byte {
+	return []byte(r.Method + "\n" + r.URL.RequestURI() + "\n" + timestamp + "\n" + crypto.Sha256sum(string(body)))
+}
+
+// readBody reads the body of r and replaces it with a reader over the same bytes.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
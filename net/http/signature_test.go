@@ -0,0 +1,110 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+func TestHMACAuth(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	keys := handlers.HMACKeys(map[string][]byte{"client-1": secret})
+	tests := []struct {
+		name     string
+		body     string
+		keyID    string
+		secret   []byte
+		tamper   func(r *http.Request)
+		wantCode int
+	}{
+		{name: "valid", body: `{"event":"push"}`, wantCode: http.StatusOK},
+		{name: "valid without body", wantCode: http.StatusOK},
+		{name: "wrong secret", body: "x", secret: []byte("other"), wantCode: http.StatusUnauthorized},
+		{name: "unknown key", body: "x", keyID: "client-2", wantCode: http.StatusUnauthorized},
+		{
+			name: "tampered body",
+			body: `{"event":"push"}`,
+			tamper: func(r *http.Request) {
+				r.Body = io.NopCloser(strings.NewReader(`{"event":"pull"}`))
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "tampered path",
+			tamper:   func(r *http.Request) { r.URL.Path = "/admin" },
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name: "stale timestamp",
+			tamper: func(r *http.Request) {
+				r.Header.Set(handlers.SignatureTimestampHeader, strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+			},
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "missing signature",
+			tamper:   func(r *http.Request) { r.Header.Del("Authorization") },
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "malformed signature",
+			tamper:   func(r *http.Request) { r.Header.Set("Authorization", `HMAC-SHA256 keyId="client-1", signature="zz"`) },
+			wantCode: http.StatusUnauthorized,
+		},
+		{name: "body too large", body: strings.Repeat("x", 2048), wantCode: http.StatusRequestEntityTooLarge},
+		{
+			name: "body too large without length",
+			body: strings.Repeat("x", 2048),
+			tamper: func(r *http.Request) {
+				r.ContentLength = -1
+			},
+			wantCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody string
+			var got *handlers.Principal
+			h := handlers.HMACAuth(keys, handlers.HMACMaxBodySize(1024))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+				got, _ = handlers.PrincipalFromContext(r.Context())
+			}))
+			req := httptest.NewRequest("POST", "/hooks?x=1", strings.NewReader(tt.body))
+			keyID, key := "client-1", secret
+			if tt.keyID != "" {
+				keyID = tt.keyID
+			}
+			if tt.secret != nil {
+				key = tt.secret
+			}
+			if err := handlers.SignRequest(req, keyID, key); err != nil {
+				t.Fatal(err)
+			}
+			if tt.tamper != nil {
+				tt.tamper(req)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			if gotBody != tt.body {
+				t.Errorf("body = %q, want %q", gotBody, tt.body)
+			}
+			if got == nil || got.Name != "client-1" || got.Scheme != handlers.AuthSchemeHMAC {
+				t.Errorf("principal = %+v", got)
+			}
+		})
+	}
+}
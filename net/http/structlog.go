@@ -0,0 +1,313 @@
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Fields available to the structured logging handlers.
+const (
+	LogFieldTime          = "time"
+	LogFieldRemoteAddr    = "remote_addr"
+	LogFieldMethod        = "method"
+	LogFieldURL           = "url"
+	LogFieldPath          = "path"
+	LogFieldHost          = "host"
+	LogFieldProto         = "proto"
+	LogFieldStatus        = "status"
+	LogFieldBytesIn       = "bytes_in"
+	LogFieldBytesOut      = "bytes_out"
+	LogFieldLatency       = "latency"
+	LogFieldRequestID     = "request_id"
+	LogFieldTraceID       = "trace_id"
+	LogFieldUserAgent     = "user_agent"
+	LogFieldReferer       = "referer"
+	LogFieldTLSVersion    = "tls_version"
+	LogFieldTLSCipher     = "tls_cipher"
+	LogFieldTLSServerName = "tls_server_name"
+)
+
+// DefaultLogFields are the fields logged by the structured logging handlers
+// unless LogFields is given.
+var DefaultLogFields = []string{
+	LogFieldTime,
+	LogFieldRemoteAddr,
+	LogFieldMethod,
+	LogFieldURL,
+	LogFieldProto,
+	LogFieldStatus,
+	LogFieldBytesIn,
+	LogFieldBytesOut,
+	LogFieldLatency,
+	LogFieldRequestID,
+	LogFieldTraceID,
+	LogFieldUserAgent,
+	LogFieldReferer,
+	LogFieldTLSVersion,
+}
+
+// StructuredLogOption configures JSONLoggingHandler and ZapLoggingHandler.
+type StructuredLogOption func(*structuredLogger)
+
+type structuredLogger struct {
+	handler         http.Handler
+	fields          []string
+	requestIDHeader string
+	sampleEvery     uint64
+	skip            []func(*http.Request) bool
+	emit            func(entries []logEntry, status int)
+
+	count uint64
+}
+
+// logEntry is one field of a structured log line.
+type logEntry struct {
+	key   string
+	value interface{}
+}
+
+// JSONLoggingHandler returns a http.Handler that wraps h and logs requests to out
+// as JSON objects, one per line, with the fields selected by LogFields.
+// The latency is logged in seconds, and fields without a value are omitted.
+//
+// Example:
+//
+//  r := mux.NewRouter()
+//  logged := handlers.JSONLoggingHandler(os.Stdout, r,
+//  	handlers.LogExcludePaths("/healthz"),
+//  	handlers.LogSampleEvery(10))
+//  http.ListenAndServe(":1123", logged)
+func JSONLoggingHandler(out io.Writer, h http.Handler, opts ...StructuredLogOption) http.Handler {
+	l := parseStructuredLogOptions(h, opts...)
+	l.emit = func(entries []logEntry, _ int) {
+		out.Write(appendJSONLog(nil, entries))
+	}
+	return l
+}
+
+// ZapLoggingHandler returns a http.Handler that wraps h and logs requests to logger,
+// with the fields selected by LogFields. Requests answered with a server error are
+// logged at error level, all others at info level.
+func ZapLoggingHandler(logger *zap.Logger, h http.Handler, opts ...StructuredLogOption) http.Handler {
+	l := parseStructuredLogOptions(h, opts...)
+	l.emit = func(entries []logEntry, status int) {
+		fields := make([]zap.Field, 0, len(entries))
+		for _, e := range entries {
+			if e.key == LogFieldTime {
+				continue
+			}
+			fields = append(fields, zap.Any(e.key, e.value))
+		}
+		if status >= http.StatusInternalServerError {
+			logger.Error("request", fields...)
+			return
+		}
+		logger.Info("request", fields...)
+	}
+	return l
+}
+
+// JSONLogFormatter returns a LogFormatter for CustomLoggingHandler that writes
+// the given fields, or DefaultLogFields, as a JSON object per request.
+func JSONLogFormatter(fields ...string) LogFormatter {
+	if len(fields) == 0 {
+		fields = DefaultLogFields
+	}
+	return func(writer io.Writer, params LogFormatterParams) {
+		writer.Write(appendJSONLog(nil, logEntries(params, fields, "", nil)))
+	}
+}
+
+// LogFields selects the fields to log, in order.
+func LogFields(fields ...string) StructuredLogOption {
+	return func(l *structuredLogger) {
+		l.fields = fields
+	}
+}
+
+// LogRequestIDHeader sets the header the request ID is read from,
+// on the request or else on the response. By default, the ID of the RequestID
+// middleware is used, or else the header it is configured with, X-Request-ID
+// unless set by RequestIDHeaderName.
+func LogRequestIDHeader(name string) StructuredLogOption {
+	return func(l *structuredLogger) {
+		l.requestIDHeader = name
+	}
+}
+
+// LogSampleEvery logs only every nth request answered without an error.
+// Requests answered with a status of 400 or above are always logged.
+func LogSampleEvery(n int) StructuredLogOption {
+	return func(l *structuredLogger) {
+		if n > 1 {
+			l.sampleEvery = uint64(n)
+		}
+	}
+}
+
+// LogExcludePaths skips logging of requests for the given URL paths, such as health checks.
+// A path ending in "/" excludes all paths below it.
+func LogExcludePaths(paths ...string) StructuredLogOption {
+	return LogSkip(func(r *http.Request) bool {
+		for _, p := range paths {
+			if r.URL.Path == p || strings.HasSuffix(p, "/") && strings.HasPrefix(r.URL.Path, p) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// LogSkip skips logging of requests for which cond returns true.
+func LogSkip(cond func(*http.Request) bool) StructuredLogOption {
+	return func(l *structuredLogger) {
+		l.skip = append(l.skip, cond)
+	}
+}
+
+func parseStructuredLogOptions(h http.Handler, opts ...StructuredLogOption) *structuredLogger {
+	l := &structuredLogger{
+		handler: h,
+		fields:  DefaultLogFields,
+	}
+	for _, option := range opts {
+		option(l)
+	}
+	return l
+}
+
+func (l *structuredLogger) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for _, skip := range l.skip {
+		if skip(req) {
+			l.handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	params := serveLogged(l.handler, w, req)
+
+	if l.sampleEvery > 1 && params.StatusCode < http.StatusBadRequest {
+		if atomic.AddUint64(&l.count, 1)%l.sampleEvery != 1 {
+			return
+		}
+	}
+	l.emit(logEntries(params, l.fields, l.requestIDHeader, w.Header()), params.StatusCode)
+}
+
+// logEntries returns the values of the given fields for params.
+// The request ID is read from requestIDHeader instead if it is not empty,
+// on the request or else on the response header if it is not nil, and
+// from the X-Request-ID request header if params has none.
+func logEntries(params LogFormatterParams, fields []string, requestIDHeader string, header http.Header) []logEntry {
+	req := params.Request
+	entries := make([]logEntry, 0, len(fields))
+	for _, field := range fields {
+		var value interface{}
+		switch field {
+		case LogFieldTime:
+			value = params.TimeStamp.Format(time.RFC3339Nano)
+		case LogFieldRemoteAddr:
+			value = req.RemoteAddr
+		case LogFieldMethod:
+			value = req.Method
+		case LogFieldURL:
+			value = params.URL.RequestURI()
+		case LogFieldPath:
+			value = params.URL.Path
+		case LogFieldHost:
+			value = req.Host
+		case LogFieldProto:
+			value = req.Proto
+		case LogFieldStatus:
+			value = params.StatusCode
+		case LogFieldBytesIn:
+			value = params.BytesIn
+		case LogFieldBytesOut:
+			value = params.Size
+		case LogFieldLatency:
+			value = params.Duration
+		case LogFieldRequestID:
+			id := params.RequestID
+			if requestIDHeader != "" {
+				id = req.Header.Get(requestIDHeader)
+				if id == "" && header != nil {
+					id = header.Get(requestIDHeader)
+				}
+			} else if id == "" {
+				// Without RequestID, a proxy in front may have set the header.
+				id = req.Header.Get(RequestIDHeader)
+			}
+			value = id
+		case LogFieldTraceID:
+			value = params.TraceID
+		case LogFieldUserAgent:
+			value = req.UserAgent()
+		case LogFieldReferer:
+			value = req.Referer()
+		case LogFieldTLSVersion:
+			if req.TLS != nil {
+				value = tlsVersionName(req.TLS.Version)
+			}
+		case LogFieldTLSCipher:
+			if req.TLS != nil {
+				value = tls.CipherSuiteName(req.TLS.CipherSuite)
+			}
+		case LogFieldTLSServerName:
+			if req.TLS != nil {
+				value = req.TLS.ServerName
+			}
+		}
+		if value == nil || value == "" {
+			continue
+		}
+		entries = append(entries, logEntry{key: field, value: value})
+	}
+	return entries
+}
+
+// appendJSONLog appends entries to buf as a JSON object followed by a newline.
+func appendJSONLog(buf []byte, entries []logEntry) []byte {
+	b := bytes.NewBuffer(buf)
+	b.WriteByte('{')
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(e.key)
+		b.Write(key)
+		b.WriteByte(':')
+		value := e.value
+		if d, ok := value.(time.Duration); ok {
+			value = d.Seconds()
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			v = []byte("null")
+		}
+		b.Write(v)
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+func tlsVersionName(version uint16) string {
+	switch version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	}
+	return "unknown"
+}
@@ -0,0 +1,212 @@
+package http_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	handlers "github.com/gofunct/functional/net/http"
+)
+
+// stepClock returns a clock starting at a fixed time that advances by 1.5s on each reading,
+// so that every logged request takes 1.5s.
+func stepClock() func() time.Time {
+	t := time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*60*60))
+	return func() time.Time {
+		now := t
+		t = t.Add(1500 * time.Millisecond)
+		return now
+	}
+}
+
+// loggedRequest returns a POST request with a body of 11 bytes, headers and TLS state.
+func loggedRequest() *http.Request {
+	req := httptest.NewRequest("POST", "https://example.com/items?id=1", strings.NewReader("hello world"))
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("User-Agent", "test/1.0")
+	req.Header.Set("Referer", "https://example.com/")
+	req.Header.Set(handlers.RequestIDHeader, "abc")
+	req.TLS = &tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256, ServerName: "example.com"}
+	return req
+}
+
+// loggedHandler reads 5 bytes of the request body and answers with status and 2 bytes.
+func loggedHandler(status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadFull(r.Body, make([]byte, 5))
+		w.WriteHeader(status)
+		io.WriteString(w, "ok")
+	})
+}
+
+func TestLoggingFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		logged func(out io.Writer, h http.Handler) http.Handler
+		want   string
+	}{
+		{
+			name:   "common",
+			logged: handlers.LoggingHandler,
+			want:   `192.0.2.1 - - [10/Oct/2000:13:55:36 -0700] "POST https://example.com/items?id=1 HTTP/1.1" 201 2` + "\n",
+		},
+		{
+			name:   "combined",
+			logged: handlers.CombinedLoggingHandler,
+			want:   `192.0.2.1 - - [10/Oct/2000:13:55:36 -0700] "POST https://example.com/items?id=1 HTTP/1.1" 201 2 "https://example.com/" "test/1.0"` + "\n",
+		},
+		{
+			name: "JSON formatter",
+			logged: func(out io.Writer, h http.Handler) http.Handler {
+				return handlers.CustomLoggingHandler(out, h, handlers.JSONLogFormatter())
+			},
+			want: `{"time":"2000-10-10T13:55:36-07:00","remote_addr":"192.0.2.1:1234","method":"POST","url":"/items?id=1","proto":"HTTP/1.1",` +
+				`"status":201,"bytes_in":5,"bytes_out":2,"latency":1.5,"request_id":"abc","user_agent":"test/1.0","referer":"https://example.com/","tls_version":"TLS 1.3"}` + "\n",
+		},
+		{
+			name: "JSON formatter fields",
+			logged: func(out io.Writer, h http.Handler) http.Handler {
+				return handlers.CustomLoggingHandler(out, h, handlers.JSONLogFormatter(handlers.LogFieldStatus, handlers.LogFieldPath, handlers.LogFieldTraceID))
+			},
+			want: `{"status":201,"path":"/items"}` + "\n",
+		},
+		{
+			name: "JSON handler",
+			logged: func(out io.Writer, h http.Handler) http.Handler {
+				return handlers.JSONLoggingHandler(out, h)
+			},
+			want: `{"time":"2000-10-10T13:55:36-07:00","remote_addr":"192.0.2.1:1234","method":"POST","url":"/items?id=1","proto":"HTTP/1.1",` +
+				`"status":201,"bytes_in":5,"bytes_out":2,"latency":1.5,"request_id":"abc","user_agent":"test/1.0","referer":"https://example.com/","tls_version":"TLS 1.3"}` + "\n",
+		},
+		{
+			name: "JSON handler fields",
+			logged: func(out io.Writer, h http.Handler) http.Handler {
+				return handlers.JSONLoggingHandler(out, h, handlers.LogFields(
+					handlers.LogFieldHost, handlers.LogFieldPath, handlers.LogFieldLatency,
+					handlers.LogFieldTLSCipher, handlers.LogFieldTLSServerName, handlers.LogFieldTraceID))
+			},
+			want: `{"host":"example.com","path":"/items","latency":1.5,"tls_cipher":"TLS_AES_128_GCM_SHA256","tls_server_name":"example.com"}` + "\n",
+		},
+		{
+			name: "JSON handler request ID header",
+			logged: func(out io.Writer, h http.Handler) http.Handler {
+				h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Amzn-Trace-Id", "Root=1-abc")
+				})
+				return handlers.JSONLoggingHandler(out, h, handlers.LogFields(handlers.LogFieldRequestID),
+					handlers.LogRequestIDHeader("X-Amzn-Trace-Id"))
+			},
+			want: `{"request_id":"Root=1-abc"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer handlers.SetLogClock(stepClock())()
+			var out bytes.Buffer
+			tt.logged(&out, loggedHandler(http.StatusCreated)).ServeHTTP(httptest.NewRecorder(), loggedRequest())
+
+			if out.String() != tt.want {
+				t.Errorf("log =\n%s\nwant\n%s", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestZapLoggingHandler(t *testing.T) {
+	defer handlers.SetLogClock(stepClock())()
+
+	var out bytes.Buffer
+	config := zap.NewProductionEncoderConfig()
+	config.TimeKey = ""
+	logger := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(config), zapcore.AddSync(&out), zapcore.DebugLevel))
+	fields := handlers.LogFields(handlers.LogFieldTime, handlers.LogFieldMethod, handlers.LogFieldStatus,
+		handlers.LogFieldBytesIn, handlers.LogFieldLatency, handlers.LogFieldRequestID)
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound, http.StatusBadGateway} {
+		handlers.ZapLoggingHandler(logger, loggedHandler(status), fields).ServeHTTP(httptest.NewRecorder(), loggedRequest())
+	}
+
+	want := `{"level":"info","msg":"request","method":"POST","status":200,"bytes_in":5,"latency":1.5,"request_id":"abc"}
+{"level":"info","msg":"request","method":"POST","status":404,"bytes_in":5,"latency":1.5,"request_id":"abc"}
+{"level":"error","msg":"request","method":"POST","status":502,"bytes_in":5,"latency":1.5,"request_id":"abc"}
+`
+	if out.String() != want {
+		t.Errorf("log =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestStructuredLogFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []handlers.StructuredLogOption
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "all",
+			paths: []string{"/a", "/b"},
+			want:  []string{"/a", "/b"},
+		},
+		{
+			name:  "sample every",
+			opts:  []handlers.StructuredLogOption{handlers.LogSampleEvery(3)},
+			paths: []string{"/1", "/2", "/error", "/3", "/4", "/5", "/6", "/7"},
+			want:  []string{"/1", "/error", "/4", "/7"},
+		},
+		{
+			name:  "sample every 1",
+			opts:  []handlers.StructuredLogOption{handlers.LogSampleEvery(1)},
+			paths: []string{"/1", "/2"},
+			want:  []string{"/1", "/2"},
+		},
+		{
+			name:  "exclude paths",
+			opts:  []handlers.StructuredLogOption{handlers.LogExcludePaths("/healthz", "/static/")},
+			paths: []string{"/healthz", "/healthz/deep", "/static/app.js", "/static", "/api"},
+			want:  []string{"/healthz/deep", "/static", "/api"},
+		},
+		{
+			name: "skip",
+			opts: []handlers.StructuredLogOption{
+				handlers.LogExcludePaths("/healthz"),
+				handlers.LogSkip(func(r *http.Request) bool { return strings.HasSuffix(r.URL.Path, ".js") }),
+			},
+			paths: []string{"/healthz", "/app.js", "/error"},
+			want:  []string{"/error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			served := 0
+			h := handlers.JSONLoggingHandler(&out, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served++
+				if r.URL.Path == "/error" {
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}), append(tt.opts, handlers.LogFields(handlers.LogFieldPath))...)
+			for _, path := range tt.paths {
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+			}
+
+			var want strings.Builder
+			for _, path := range tt.want {
+				want.WriteString(`{"path":"` + path + `"}` + "\n")
+			}
+			if out.String() != want.String() {
+				t.Errorf("log =\n%s\nwant\n%s", out.String(), want.String())
+			}
+			if served != len(tt.paths) {
+				t.Errorf("served %d requests, want %d", served, len(tt.paths))
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"html/template"
+	"net/http"
+)
+
+// TemplateFuncMap returns the html/template functions bound to the request r:
+//
+//  cspNonce   the Content-Security-Policy nonce generated by SecurityHeaders
+//  csrfField  a hidden form field carrying the CSRF token, as CSRFTemplateField
+//  csrfToken  a masked CSRF token, as CSRFToken
+//
+// Templates are parsed with TemplateFuncMap(nil), whose functions return empty values,
+// and each request executes a clone bound to it. The functions can be merged into
+// another FuncMap, such as the one of fmap.HtmlFuncMap.
+//
+// Example:
+//
+//  page := template.Must(template.New("page").Funcs(handlers.TemplateFuncMap(nil)).Parse(src))
+//  ...
+//  t := template.Must(page.Clone()).Funcs(handlers.TemplateFuncMap(r))
+//  t.Execute(w, data)
+func TemplateFuncMap(r *http.Request) template.FuncMap {
+	if r == nil {
+		return template.FuncMap{
+			"cspNonce":  func() string { return "" },
+			"csrfField": func() template.HTML { return "" },
+			"csrfToken": func() string { return "" },
+		}
+	}
+	nonce := CSPNonceFromContext(r.Context())
+	return template.FuncMap{
+		"cspNonce":  func() string { return nonce },
+		"csrfField": func() template.HTML { return CSRFTemplateField(r) },
+		"csrfToken": func() string { return CSRFToken(r) },
+	}
+}